@@ -131,7 +131,21 @@ func (api AmazonMWSAPI) GetMatchingProductForId(idType string, idList []string)
 	params["IdType"] = idType
 	params["MarketplaceId"] = string(api.MarketplaceId)
 
-	return api.fastSignAndFetchViaPost("GetMatchingProductForId", "/Products/2011-10-01", params, nil)
+	return api.cached("GetMatchingProductForId", append([]string{idType}, idList...), func() (string, Quota, error) {
+		return api.fastSignAndFetchViaPost("GetMatchingProductForId", "/Products/2011-10-01", params, nil)
+	})
+}
+
+// GetProductCategoriesForASIN returns the browse node ancestry of a product.
+func (api AmazonMWSAPI) GetProductCategoriesForASIN(asin string) (string, Quota, error) {
+	params := make(map[string]string)
+
+	params["ASIN"] = asin
+	params["MarketplaceId"] = string(api.MarketplaceId)
+
+	return api.cached("GetProductCategoriesForASIN", []string{asin}, func() (string, Quota, error) {
+		return api.fastSignAndFetchViaPost("GetProductCategoriesForASIN", "/Products/2011-10-01", params, nil)
+	})
 }
 
 func (api AmazonMWSAPI) GetMyFeesEstimate(items []FeeEstimateRequest) (string, Quota, error) {
@@ -164,11 +178,6 @@ func (api AmazonMWSAPI) SubmitFeed(content []byte, feedType string) (string, Quo
 	return api.fastSignAndFetchViaPost("SubmitFeed", "/Feeds/2009-01-01", params, content)
 }
 
-func (api AmazonMWSAPI) ListMarketplaceParticipations() (string, Quota, error) {
-	params := make(map[string]string)
-	return api.fastSignAndFetchViaPost("ListMarketplaceParticipations", "/Sellers/2011-07-01", params, nil)
-}
-
 type RequestReportRequest struct {
 	ReportType        string
 	StartDate         *string
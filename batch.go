@@ -0,0 +1,237 @@
+package amazonmws
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+const (
+	// MaxASINsPerRequest is the largest ASINList Amazon accepts in a single
+	// GetLowestOfferListingsForASIN or GetCompetitivePricingForASIN request.
+	MaxASINsPerRequest = 20
+
+	// MaxIdsPerMatchingProductRequest is the largest IdList Amazon accepts in
+	// a single GetMatchingProductForId request.
+	MaxIdsPerMatchingProductRequest = 5
+
+	batchConcurrency = 4
+)
+
+// BatchResult is the outcome for a single identifier of a batch call.
+type BatchResult struct {
+	Id     string
+	Status string
+	// Result holds the raw XML inside the per-identifier result element.
+	Result string
+	Err    error
+}
+
+type batchItem struct {
+	ASIN   string    `xml:"ASIN,attr"`
+	Id     string    `xml:"Id,attr"`
+	Status string    `xml:"status,attr"`
+	Error  *MWSError `xml:"Error"`
+	Inner  string    `xml:",innerxml"`
+}
+
+type batchFetcher func(ctx context.Context, ids []string) (string, Quota, error)
+
+// GetLowestOfferListingsForASINBatch looks up any number of ASINs, splitting
+// them into requests of MaxASINsPerRequest that run concurrently within the
+// action's rate limit. The results are keyed by ASIN.
+func (api AmazonMWSAPI) GetLowestOfferListingsForASINBatch(ctx context.Context, asins []string) (map[string]BatchResult, error) {
+	fetch := func(ctx context.Context, ids []string) (string, Quota, error) {
+		return api.WithContext(ctx).GetLowestOfferListingsForASIN(ids)
+	}
+
+	return runBatch(ctx, api.telemetry(), api.pacer("GetLowestOfferListingsForASIN"), api.batchCache("GetLowestOfferListingsForASIN"), "GetLowestOfferListingsForASIN", asins, MaxASINsPerRequest, fetch)
+}
+
+// GetCompetitivePricingForASINBatch looks up any number of ASINs, splitting
+// them into requests of MaxASINsPerRequest that run concurrently within the
+// action's rate limit. The results are keyed by ASIN.
+func (api AmazonMWSAPI) GetCompetitivePricingForASINBatch(ctx context.Context, asins []string) (map[string]BatchResult, error) {
+	fetch := func(ctx context.Context, ids []string) (string, Quota, error) {
+		return api.WithContext(ctx).GetCompetitivePricingForASIN(ids)
+	}
+
+	return runBatch(ctx, api.telemetry(), api.pacer("GetCompetitivePricingForASIN"), api.batchCache("GetCompetitivePricingForASIN"), "GetCompetitivePricingForASIN", asins, MaxASINsPerRequest, fetch)
+}
+
+// GetMatchingProductForIdBatch looks up any number of identifiers of the given
+// type, splitting them into requests of MaxIdsPerMatchingProductRequest that
+// run concurrently within the action's rate limit. The results are keyed by
+// identifier.
+func (api AmazonMWSAPI) GetMatchingProductForIdBatch(ctx context.Context, idType string, idList []string) (map[string]BatchResult, error) {
+	// Results are cached per identifier by runBatch, not per request.
+	uncached := api
+	uncached.Cache = nil
+	fetch := func(ctx context.Context, ids []string) (string, Quota, error) {
+		return uncached.WithContext(ctx).GetMatchingProductForId(idType, ids)
+	}
+
+	return runBatch(ctx, api.telemetry(), api.pacer("GetMatchingProductForId"), api.batchCache("GetMatchingProductForId", idType), "GetMatchingProductForId", idList, MaxIdsPerMatchingProductRequest, fetch)
+}
+
+// chunkStrings splits ids into consecutive slices of at most size entries,
+// dropping empty and duplicate identifiers.
+func chunkStrings(ids []string, size int) [][]string {
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+
+	var chunks [][]string
+	for len(unique) > 0 {
+		n := size
+		if len(unique) < n {
+			n = len(unique)
+		}
+		chunks = append(chunks, unique[:n:n])
+		unique = unique[n:]
+	}
+
+	return chunks
+}
+
+func runBatch(ctx context.Context, tel telemetry, pacer pacer, cache *batchCache, action string, ids []string, size int, fetch batchFetcher) (map[string]BatchResult, error) {
+	results := make(map[string]BatchResult, len(ids))
+	misses := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if r, ok := cache.get(id); ok {
+			results[id] = r
+			continue
+		}
+		misses = append(misses, id)
+	}
+	chunks := chunkStrings(misses, size)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	work := make(chan []string)
+
+	for w := 0; w < batchConcurrency && w < len(chunks); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chunk := range work {
+				chunkResults := fetchChunk(ctx, tel, pacer, action, chunk, fetch)
+
+				mu.Lock()
+				for _, r := range chunkResults {
+					results[r.Id] = r
+					cache.set(r)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	sent := 0
+	for _, chunk := range chunks {
+		select {
+		case work <- chunk:
+			sent++
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	// Chunks never handed to a worker were not attempted; say so rather than
+	// leave their identifiers out.
+	for _, chunk := range chunks[sent:] {
+		for _, r := range failChunk(chunk, ctx.Err()) {
+			results[r.Id] = r
+		}
+	}
+
+	return results, ctx.Err()
+}
+
+// fetchChunk requests a single chunk, retrying while Amazon throttles it, and
+// returns one result per identifier in the chunk.
+func fetchChunk(ctx context.Context, tel telemetry, pacer pacer, action string, chunk []string, fetch batchFetcher) []BatchResult {
+	body, _, err := fetchWithRetry(ctx, tel, action, pacer, len(chunk), func(ctx context.Context) (string, Quota, error) {
+		return fetch(ctx, chunk)
+	})
+	if err != nil {
+		return failChunk(chunk, err)
+	}
+
+	items, err := parseBatchItems(body, action+"Result")
+	if err != nil {
+		return failChunk(chunk, err)
+	}
+
+	results := make([]BatchResult, 0, len(chunk))
+	found := make(map[string]bool, len(items))
+	for _, item := range items {
+		id := item.Id
+		if id == "" {
+			id = item.ASIN
+		}
+		found[id] = true
+
+		r := BatchResult{Id: id, Status: item.Status, Result: item.Inner}
+		if item.Error != nil {
+			r.Err = item.Error
+		}
+		results = append(results, r)
+	}
+
+	for _, id := range chunk {
+		if !found[id] {
+			results = append(results, BatchResult{Id: id, Err: fmt.Errorf("amazonmws: %s returned no result for %s", action, id)})
+		}
+	}
+
+	return results
+}
+
+func failChunk(chunk []string, err error) []BatchResult {
+	results := make([]BatchResult, len(chunk))
+	for i, id := range chunk {
+		results[i] = BatchResult{Id: id, Err: err}
+	}
+	return results
+}
+
+// parseBatchItems decodes every element named resultName in body.
+func parseBatchItems(body, resultName string) ([]batchItem, error) {
+	var items []batchItem
+
+	decoder := xml.NewDecoder(strings.NewReader(body))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return items, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != resultName {
+			continue
+		}
+
+		var item batchItem
+		if err := decoder.DecodeElement(&item, &start); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+}
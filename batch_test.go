@@ -0,0 +1,129 @@
+package amazonmws
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestChunkStrings(t *testing.T) {
+	ids := make([]string, 0, 45)
+	for i := 0; i < 45; i++ {
+		ids = append(ids, fmt.Sprintf("B%09d", i))
+	}
+	ids = append(ids, "B000000001", "")
+
+	chunks := chunkStrings(ids, MaxASINsPerRequest)
+
+	assert.Len(t, chunks, 3)
+	assert.Len(t, chunks[0], 20)
+	assert.Len(t, chunks[1], 20)
+	assert.Len(t, chunks[2], 5)
+}
+
+func TestRunBatch(t *testing.T) {
+	ids := make([]string, 0, 12)
+	for i := 0; i < 12; i++ {
+		ids = append(ids, fmt.Sprintf("ID%d", i))
+	}
+
+	var mu sync.Mutex
+	var calls int
+	fetch := func(ctx context.Context, chunk []string) (string, Quota, error) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+
+		assert.True(t, len(chunk) <= MaxIdsPerMatchingProductRequest)
+
+		var body strings.Builder
+		body.WriteString(`<GetMatchingProductForIdResponse>`)
+		for _, id := range chunk {
+			if id == "ID3" {
+				body.WriteString(`<GetMatchingProductForIdResult Id="ID3" IdType="UPC" status="ClientError"><Error><Type>Sender</Type><Code>InvalidParameterValue</Code><Message>Invalid UPC identifier ID3</Message></Error></GetMatchingProductForIdResult>`)
+				continue
+			}
+			if id == "ID7" {
+				continue
+			}
+			body.WriteString(`<GetMatchingProductForIdResult Id="` + id + `" IdType="UPC" status="Success"><Products/></GetMatchingProductForIdResult>`)
+		}
+		body.WriteString(`</GetMatchingProductForIdResponse>`)
+
+		return body.String(), Quota{}, nil
+	}
+
+	limiter := NewLimiter(100, time.Millisecond)
+	results, err := runBatch(context.Background(), AmazonMWSAPI{}.telemetry(), pacer{limiter: limiter}, nil, "GetMatchingProductForId", ids, MaxIdsPerMatchingProductRequest, fetch)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Len(t, results, 12)
+
+	assert.Equal(t, "Success", results["ID0"].Status)
+	assert.Equal(t, "<Products/>", results["ID0"].Result)
+	assert.Nil(t, results["ID0"].Err)
+
+	assert.Equal(t, "ClientError", results["ID3"].Status)
+	assert.Equal(t, &MWSError{Type: "Sender", Code: "InvalidParameterValue", Message: "Invalid UPC identifier ID3"}, results["ID3"].Err)
+
+	assert.NotNil(t, results["ID7"].Err)
+}
+
+func TestRunBatchRetriesThrottledChunks(t *testing.T) {
+	throttled := `<ErrorResponse><Error><Type>Sender</Type><Code>RequestThrottled</Code><Message>Request is throttled</Message></Error><RequestID>abc</RequestID></ErrorResponse>`
+
+	var calls int
+	fetch := func(ctx context.Context, chunk []string) (string, Quota, error) {
+		calls++
+		if calls == 1 {
+			return throttled, Quota{}, nil
+		}
+		return `<R><GetCompetitivePricingForASINResult ASIN="B1" status="Success"/></R>`, Quota{}, nil
+	}
+
+	limiter := NewLimiter(20, time.Millisecond)
+	results, err := runBatch(context.Background(), AmazonMWSAPI{}.telemetry(), pacer{limiter: limiter}, nil, "GetCompetitivePricingForASIN", []string{"B1"}, MaxASINsPerRequest, fetch)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, calls)
+	assert.Nil(t, results["B1"].Err)
+	assert.Equal(t, "Success", results["B1"].Status)
+}
+
+func TestRunBatchReportsUnsentChunks(t *testing.T) {
+	ids := make([]string, 0, 10)
+	for i := 0; i < 10; i++ {
+		ids = append(ids, fmt.Sprintf("B%d", i))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	fetched := make(map[string]bool)
+	fetch := func(ctx context.Context, chunk []string) (string, Quota, error) {
+		mu.Lock()
+		fetched[chunk[0]] = true
+		mu.Unlock()
+		cancel()
+
+		return `<R><GetCompetitivePricingForASINResult ASIN="` + chunk[0] + `" status="Success"/></R>`, Quota{}, nil
+	}
+
+	limiter := NewLimiter(20, time.Millisecond)
+	results, err := runBatch(ctx, AmazonMWSAPI{}.telemetry(), pacer{limiter: limiter}, nil, "GetCompetitivePricingForASIN", ids, 1, fetch)
+
+	assert.Equal(t, context.Canceled, err)
+	assert.Len(t, results, 10)
+	assert.True(t, len(fetched) < 10)
+	for _, id := range ids {
+		if !fetched[id] {
+			assert.Equal(t, context.Canceled, results[id].Err)
+		}
+	}
+}
@@ -0,0 +1,315 @@
+package amazonmws
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheStore keeps cached responses until their TTL has passed.
+// Implementations must be safe for concurrent use.
+type CacheStore interface {
+	Get(key string) (string, bool)
+	Set(key, value string, ttl time.Duration)
+}
+
+// DefaultCacheTTLs are the actions ResponseCache caches unless told
+// otherwise. Catalog data rarely changes, so a day is a safe default.
+var DefaultCacheTTLs = map[string]time.Duration{
+	"GetMatchingProductForId":     24 * time.Hour,
+	"GetProductCategoriesForASIN": 24 * time.Hour,
+}
+
+// ResponseCache answers repeated lookups from Store instead of spending quota
+// on them. Only the actions in TTLs are cached, each for its own duration,
+// and only responses without any error, including one for a single
+// identifier, are kept. Entries are keyed on the action,
+// marketplace and identifiers, and batch calls cache every identifier on its
+// own so that only the misses are sent to Amazon.
+type ResponseCache struct {
+	Store CacheStore
+	TTLs  map[string]time.Duration
+}
+
+// NewResponseCache caches the actions in DefaultCacheTTLs in store.
+func NewResponseCache(store CacheStore) *ResponseCache {
+	ttls := make(map[string]time.Duration, len(DefaultCacheTTLs))
+	for action, ttl := range DefaultCacheTTLs {
+		ttls[action] = ttl
+	}
+
+	return &ResponseCache{Store: store, TTLs: ttls}
+}
+
+func (c *ResponseCache) ttl(action string) (time.Duration, bool) {
+	if c == nil || c.Store == nil {
+		return 0, false
+	}
+	ttl, ok := c.TTLs[action]
+
+	return ttl, ok && ttl > 0
+}
+
+func cacheKey(action, marketplaceId string, ids ...string) string {
+	return action + "/" + marketplaceId + "/" + strings.Join(ids, ",")
+}
+
+// cached returns the response to action for ids from api.Cache, or calls fetch
+// and caches what it returns. Cached responses report an empty Quota.
+func (api AmazonMWSAPI) cached(action string, ids []string, fetch func() (string, Quota, error)) (string, Quota, error) {
+	ttl, ok := api.Cache.ttl(action)
+	if !ok {
+		return fetch()
+	}
+
+	key := cacheKey(action, api.MarketplaceId, ids...)
+	if body, ok := api.Cache.Store.Get(key); ok {
+		api.logger().Debug("mws cache hit", "action", action, "key", key)
+		return body, Quota{}, nil
+	}
+
+	body, quota, err := fetch()
+	if err == nil && !containsError(body) {
+		api.Cache.Store.Set(key, body, ttl)
+	}
+
+	return body, quota, err
+}
+
+// containsError reports whether body has an Error element anywhere, either as
+// an ErrorResponse or in the result for one of several identifiers. A body
+// that does not parse counts as an error.
+func containsError(body string) bool {
+	decoder := xml.NewDecoder(strings.NewReader(body))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return false
+		}
+		if err != nil {
+			return true
+		}
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local == "Error" {
+			return true
+		}
+	}
+}
+
+// batchCache stores the results of a batch call one identifier at a time.
+type batchCache struct {
+	store CacheStore
+	ttl   time.Duration
+	key   func(id string) string
+}
+
+// batchCache returns the cache for action's batch results, or nil when
+// action is not cached.
+func (api AmazonMWSAPI) batchCache(action string, ids ...string) *batchCache {
+	ttl, ok := api.Cache.ttl(action)
+	if !ok {
+		return nil
+	}
+
+	return &batchCache{
+		store: api.Cache.Store,
+		ttl:   ttl,
+		// Items are stored apart from whole responses to the same request.
+		key: func(id string) string {
+			return "item:" + cacheKey(action, api.MarketplaceId, append(append([]string(nil), ids...), id)...)
+		},
+	}
+}
+
+type cachedBatchResult struct {
+	Status string `json:"status"`
+	Result string `json:"result"`
+}
+
+func (c *batchCache) get(id string) (BatchResult, bool) {
+	if c == nil {
+		return BatchResult{}, false
+	}
+
+	value, ok := c.store.Get(c.key(id))
+	if !ok {
+		return BatchResult{}, false
+	}
+	var cached cachedBatchResult
+	if err := json.Unmarshal([]byte(value), &cached); err != nil {
+		return BatchResult{}, false
+	}
+
+	return BatchResult{Id: id, Status: cached.Status, Result: cached.Result}, true
+}
+
+func (c *batchCache) set(r BatchResult) {
+	if c == nil || r.Err != nil {
+		return
+	}
+
+	value, err := json.Marshal(cachedBatchResult{Status: r.Status, Result: r.Result})
+	if err != nil {
+		return
+	}
+	c.store.Set(c.key(r.Id), string(value), c.ttl)
+}
+
+type memoryCacheEntry struct {
+	key     string
+	value   string
+	expires time.Time
+}
+
+// MemoryCache is a CacheStore that keeps at most Capacity entries in memory,
+// evicting the least recently used one when it is full. The zero value is an
+// empty cache without a limit.
+type MemoryCache struct {
+	Capacity int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+func NewMemoryCache(capacity int) *MemoryCache {
+	return &MemoryCache{
+		Capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// lazyInit prepares a MemoryCache that was not made by NewMemoryCache.
+func (c *MemoryCache) lazyInit() {
+	if c.entries == nil {
+		c.entries = make(map[string]*list.Element)
+		c.order = list.New()
+	}
+}
+
+func (c *MemoryCache) Get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lazyInit()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	entry := element.Value.(*memoryCacheEntry)
+	if time.Now().After(entry.expires) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return "", false
+	}
+	c.order.MoveToFront(element)
+
+	return entry.value, true
+}
+
+func (c *MemoryCache) Set(key, value string, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lazyInit()
+
+	expires := time.Now().Add(ttl)
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*memoryCacheEntry)
+		entry.value = value
+		entry.expires = expires
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&memoryCacheEntry{key: key, value: value, expires: expires})
+	for c.Capacity > 0 && c.order.Len() > c.Capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*memoryCacheEntry).key)
+	}
+}
+
+// Len returns the number of entries held, including expired ones not yet
+// evicted.
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lazyInit()
+
+	return c.order.Len()
+}
+
+// FileCache is a CacheStore that keeps one JSON file per entry in Dir, so the
+// cache survives restarts and can be shared between processes on one host.
+// Expired files are removed when they are next read.
+type FileCache struct {
+	Dir string
+}
+
+type fileCacheEntry struct {
+	Key     string    `json:"key"`
+	Expires time.Time `json:"expires"`
+	Value   string    `json:"value"`
+}
+
+func (c FileCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (c FileCache) Get(key string) (string, bool) {
+	path := c.path(key)
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+
+	var entry fileCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Key != key {
+		return "", false
+	}
+	if time.Now().After(entry.Expires) {
+		os.Remove(path)
+		return "", false
+	}
+
+	return entry.Value, true
+}
+
+// Set writes the entry to a temporary file first, so that concurrent readers
+// never see it half written. Errors are ignored; the entry is simply not
+// cached.
+func (c FileCache) Set(key, value string, ttl time.Duration) {
+	data, err := json.Marshal(fileCacheEntry{Key: key, Expires: time.Now().Add(ttl), Value: value})
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return
+	}
+
+	tmp, err := ioutil.TempFile(c.Dir, ".tmp-")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), c.path(key)); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
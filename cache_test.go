@@ -0,0 +1,160 @@
+package amazonmws
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMemoryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewMemoryCache(2)
+	cache.Set("a", "1", time.Hour)
+	cache.Set("b", "2", time.Hour)
+	cache.Get("a")
+	cache.Set("c", "3", time.Hour)
+
+	_, ok := cache.Get("b")
+	assert.False(t, ok)
+	value, ok := cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, "1", value)
+	assert.Equal(t, 2, cache.Len())
+
+	cache.Set("d", "4", -time.Second)
+	_, ok = cache.Get("d")
+	assert.False(t, ok)
+}
+
+func TestMemoryCacheZeroValue(t *testing.T) {
+	cache := &MemoryCache{Capacity: 1}
+	_, ok := cache.Get("a")
+	assert.False(t, ok)
+
+	cache.Set("a", "1", time.Hour)
+	cache.Set("b", "2", time.Hour)
+	value, ok := cache.Get("b")
+	assert.True(t, ok)
+	assert.Equal(t, "2", value)
+	assert.Equal(t, 1, cache.Len())
+}
+
+func TestFileCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cache")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	cache := FileCache{Dir: dir}
+	cache.Set("GetProductCategoriesForASIN/ATVPDKIKX0DER/B1", "<body/>", time.Hour)
+	value, ok := cache.Get("GetProductCategoriesForASIN/ATVPDKIKX0DER/B1")
+	assert.True(t, ok)
+	assert.Equal(t, "<body/>", value)
+
+	_, ok = cache.Get("GetProductCategoriesForASIN/ATVPDKIKX0DER/B2")
+	assert.False(t, ok)
+
+	cache.Set("expired", "<body/>", -time.Second)
+	_, ok = cache.Get("expired")
+	assert.False(t, ok)
+	files, _ := ioutil.ReadDir(dir)
+	assert.Len(t, files, 1)
+}
+
+func TestCachedLookupsSkipAmazon(t *testing.T) {
+	sent := 0
+	amazon := func(next RoundTripper) RoundTripper {
+		return RoundTripperFunc(func(req *Request) (*Response, error) {
+			sent++
+			if req.Params["ASIN"] == "BAD" {
+				return &Response{StatusCode: 400, Body: `<ErrorResponse><Error><Type>Sender</Type><Code>InvalidParameterValue</Code><Message>Invalid ASIN</Message></Error><RequestID>r-1</RequestID></ErrorResponse>`}, nil
+			}
+			return &Response{StatusCode: 200, Body: `<GetProductCategoriesForASINResponse/>`}, nil
+		})
+	}
+	api := AmazonMWSAPI{
+		Host:          "mws.amazonservices.com",
+		MarketplaceId: "ATVPDKIKX0DER",
+		Middleware:    []Middleware{amazon},
+		Cache:         NewResponseCache(NewMemoryCache(10)),
+	}
+
+	for i := 0; i < 2; i++ {
+		body, _, err := api.GetProductCategoriesForASIN("B1")
+		assert.Nil(t, err)
+		assert.Equal(t, `<GetProductCategoriesForASINResponse/>`, body)
+	}
+	assert.Equal(t, 1, sent)
+
+	api.MarketplaceId = "A1F83G8C2ARO7P"
+	api.GetProductCategoriesForASIN("B1")
+	assert.Equal(t, 2, sent)
+
+	api.GetProductCategoriesForASIN("BAD")
+	api.GetProductCategoriesForASIN("BAD")
+	assert.Equal(t, 4, sent)
+
+	api.GetLowestOfferListingsForASIN([]string{"B1"})
+	api.GetLowestOfferListingsForASIN([]string{"B1"})
+	assert.Equal(t, 6, sent)
+}
+
+func TestCachedLookupsSkipItemErrors(t *testing.T) {
+	sent := 0
+	amazon := func(next RoundTripper) RoundTripper {
+		return RoundTripperFunc(func(req *Request) (*Response, error) {
+			sent++
+			return &Response{StatusCode: 200, Body: `<GetMatchingProductForIdResponse>
+  <GetMatchingProductForIdResult Id="B1" IdType="ASIN" status="Success"><Products/></GetMatchingProductForIdResult>
+  <GetMatchingProductForIdResult Id="B2" IdType="ASIN" status="ClientError"><Error><Type>Sender</Type><Code>InvalidParameterValue</Code><Message>Invalid ASIN identifier B2</Message></Error></GetMatchingProductForIdResult>
+</GetMatchingProductForIdResponse>`}, nil
+		})
+	}
+	api := AmazonMWSAPI{
+		Host:          "mws.amazonservices.com",
+		MarketplaceId: "ATVPDKIKX0DER",
+		Middleware:    []Middleware{amazon},
+		Cache:         NewResponseCache(NewMemoryCache(10)),
+	}
+
+	api.GetMatchingProductForId("ASIN", []string{"B1", "B2"})
+	api.GetMatchingProductForId("ASIN", []string{"B1", "B2"})
+	assert.Equal(t, 2, sent)
+
+	assert.False(t, containsError(`<GetProductCategoriesForASINResponse/>`))
+	assert.True(t, containsError(`<GetProductCategoriesForASINResponse>`))
+}
+
+func TestRunBatchFetchesOnlyCacheMisses(t *testing.T) {
+	api := AmazonMWSAPI{MarketplaceId: "ATVPDKIKX0DER", Cache: NewResponseCache(NewMemoryCache(10))}
+	cache := api.batchCache("GetMatchingProductForId", "UPC")
+	cache.set(BatchResult{Id: "ID1", Status: "Success", Result: "<Products>cached</Products>"})
+
+	var requested [][]string
+	fetch := func(ctx context.Context, chunk []string) (string, Quota, error) {
+		requested = append(requested, chunk)
+
+		var body strings.Builder
+		for _, id := range chunk {
+			body.WriteString(`<GetMatchingProductForIdResult Id="` + id + `" IdType="UPC" status="Success"><Products/></GetMatchingProductForIdResult>`)
+		}
+		return body.String(), Quota{}, nil
+	}
+
+	limiter := NewLimiter(100, time.Millisecond)
+	results, err := runBatch(context.Background(), api.telemetry(), pacer{limiter: limiter}, cache, "GetMatchingProductForId", []string{"ID1", "ID2"}, MaxIdsPerMatchingProductRequest, fetch)
+
+	assert.Nil(t, err)
+	assert.Equal(t, [][]string{{"ID2"}}, requested)
+	assert.Equal(t, "<Products>cached</Products>", results["ID1"].Result)
+	assert.Equal(t, "<Products/>", results["ID2"].Result)
+
+	cached, ok := cache.get("ID2")
+	assert.True(t, ok)
+	assert.Equal(t, "Success", cached.Status)
+
+	_, ok = api.batchCache("GetMatchingProductForId", "EAN").get("ID2")
+	assert.False(t, ok)
+}
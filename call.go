@@ -0,0 +1,43 @@
+package amazonmws
+
+import (
+	"context"
+)
+
+// Call sends any MWS operation, including ones this package does not wrap,
+// through the same signing, rate limiting, throttle retry and error handling
+// as the typed methods. section is the API path, such as
+// "/Orders/2013-09-01", and version its version; an empty version uses the
+// one registered for section with RegisterSection.
+//
+// params holds the operation's own parameters and is not modified. When body
+// is not nil it is sent as the request body and the parameters go in the
+// query string, as SubmitFeed does. The raw response body is returned; an
+// ErrorResponse from Amazon is returned as a *MWSError along with the body.
+//
+// ctx bounds the whole call: waiting for quota, retries and each HTTP
+// exchange, which is cut off at ctx's deadline. A request already in flight
+// when ctx is canceled cannot be aborted, but Call returns ctx's error as
+// soon as it completes rather than its response.
+func (api AmazonMWSAPI) Call(ctx context.Context, section, version, action string, params map[string]string, body []byte) (string, Quota, error) {
+	if version == "" {
+		var ok bool
+		if version, ok = sectionVersion(section); !ok {
+			return "", Quota{}, unknownSectionError(section)
+		}
+	}
+
+	tel := api.telemetry()
+	api, span := api.WithContext(ctx).startRequestSpan(tel.tracer, action, section, params)
+	responseBody, quota, err := fetchWithRetry(api.context(), tel, action, api.pacer(action), 1, func(ctx context.Context) (string, Quota, error) {
+		query := make(map[string]string, len(params))
+		for k, v := range params {
+			query[k] = v
+		}
+
+		return api.WithContext(ctx).signAndFetch(action, section, version, query, body)
+	})
+	endSpan(span, err)
+
+	return responseBody, quota, err
+}
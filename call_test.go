@@ -0,0 +1,130 @@
+package amazonmws
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCallUnknownSection(t *testing.T) {
+	api := AmazonMWSAPI{Host: "mws.amazonservices.com"}
+
+	_, _, err := api.Call(context.Background(), "/Orders/2013-09-01", "", "ListOrders", nil, nil)
+	assert.EqualError(t, err, "amazonmws: unknown API section /Orders/2013-09-01")
+	assert.True(t, errors.Is(err, ErrUnknownSection))
+}
+
+func TestRegisterSectionConcurrently(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			RegisterSection("/CustomSection/2020-01-01", "2020-01-01")
+		}()
+		go func() {
+			defer wg.Done()
+			Sections()
+		}()
+	}
+	wg.Wait()
+
+	version, ok := sectionVersion("/CustomSection/2020-01-01")
+	assert.True(t, ok)
+	assert.Equal(t, "2020-01-01", version)
+
+	versionsMu.Lock()
+	delete(versions, "/CustomSection/2020-01-01")
+	versionsMu.Unlock()
+}
+
+func TestCallCanceled(t *testing.T) {
+	limitersMu.Lock()
+	saved, registered := defaultQuotas["CustomAction"]
+	limitersMu.Unlock()
+	defer func() {
+		limitersMu.Lock()
+		defer limitersMu.Unlock()
+		if registered {
+			defaultQuotas["CustomAction"] = saved
+		} else {
+			delete(defaultQuotas, "CustomAction")
+		}
+		delete(limiters, "call-test/CustomAction")
+	}()
+
+	RegisterQuota("CustomAction", 0, time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	api := AmazonMWSAPI{Host: "mws.amazonservices.com", SellerId: "call-test"}
+	_, _, err := api.Call(ctx, "/Products/2011-10-01", "", "CustomAction", map[string]string{"Id": "1"}, nil)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestDoContext(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	exchange := func(ctx context.Context) error {
+		req := fasthttp.AcquireRequest()
+		resp := fasthttp.AcquireResponse()
+		defer fasthttp.ReleaseRequest(req)
+		defer fasthttp.ReleaseResponse(resp)
+		req.SetRequestURI(server.URL)
+
+		return doContext(ctx, req, resp)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	assert.Equal(t, context.DeadlineExceeded, exchange(ctx))
+	assert.True(t, time.Since(start) < time.Second)
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, exchange(canceled))
+}
+
+func TestCallPacesSellerFromParams(t *testing.T) {
+	limitersMu.Lock()
+	saved, registered := defaultQuotas["OnBehalfAction"]
+	limitersMu.Unlock()
+	t.Cleanup(func() {
+		limitersMu.Lock()
+		defer limitersMu.Unlock()
+		if registered {
+			defaultQuotas["OnBehalfAction"] = saved
+		} else {
+			delete(defaultQuotas, "OnBehalfAction")
+		}
+		delete(limiters, "call-test-a/OnBehalfAction")
+		delete(limiters, "call-test-b/OnBehalfAction")
+	})
+
+	RegisterQuota("OnBehalfAction", 10, time.Hour)
+	onBehalf := func(next RoundTripper) RoundTripper {
+		return RoundTripperFunc(func(req *Request) (*Response, error) {
+			req.Params["SellerId"] = "call-test-b"
+			return next.RoundTrip(req)
+		})
+	}
+	api := AmazonMWSAPI{Host: offlineHost, SellerId: "call-test-a", Middleware: []Middleware{onBehalf}}
+
+	_, _, err := api.Call(context.Background(), "/Products/2011-10-01", "", "OnBehalfAction", nil, nil)
+
+	assert.NotNil(t, err)
+	assert.InDelta(t, 10, limiterFor("call-test-a", "OnBehalfAction", "").tokens, 0.01)
+	assert.InDelta(t, 9, limiterFor("call-test-b", "OnBehalfAction", "").tokens, 0.01)
+}
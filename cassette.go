@@ -0,0 +1,165 @@
+package amazonmws
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sync"
+)
+
+// ErrCassetteMiss is returned in replay mode for a request the cassette has
+// no recording of.
+var ErrCassetteMiss = errors.New("amazonmws: request not found in cassette")
+
+type CassetteMode int
+
+const (
+	// CassetteRecord sends every request to Amazon and saves it with its
+	// response, replacing the cassette file.
+	CassetteRecord CassetteMode = iota
+	// CassetteReplay answers every request from the cassette without any
+	// network access.
+	CassetteReplay
+)
+
+// cassetteIgnored are the parameters that differ between otherwise identical
+// requests, or are scrubbed before saving, so matching leaves them out.
+var cassetteIgnored = map[string]bool{
+	"AWSAccessKeyId": true,
+	"MWSAuthToken":   true,
+	"Signature":      true,
+	"Timestamp":      true,
+}
+
+// Interaction is one recorded request and the response Amazon gave.
+type Interaction struct {
+	Action   string            `json:"action"`
+	Section  string            `json:"section"`
+	Params   map[string]string `json:"params"`
+	Body     string            `json:"body,omitempty"`
+	Response Response          `json:"response"`
+}
+
+func (i Interaction) key() string {
+	return cassetteKey(i.Action, i.Section, i.Params, i.Body)
+}
+
+func cassetteKey(action, section string, params map[string]string, body string) string {
+	return matchKey(action, section, params, body, cassetteIgnored)
+}
+
+// Cassette records MWS requests and responses to a JSON file and serves them
+// back, so tests can run against real Amazon payloads offline. Credentials
+// and the timestamp are removed before anything is saved, and requests are
+// matched on everything else. Install it as middleware:
+//
+//	cassette, err := amazonmws.NewCassette("testdata/orders.json", amazonmws.CassetteReplay)
+//	api.Middleware = append(api.Middleware, cassette.Middleware)
+//
+// Identical requests are answered in the order they were recorded, and the
+// last answer is repeated once they run out.
+type Cassette struct {
+	Path string
+	Mode CassetteMode
+
+	mu           sync.Mutex
+	interactions []Interaction
+	served       map[string]int
+}
+
+// NewCassette opens the cassette at path. In replay mode the file must exist;
+// in record mode it is created on the first request.
+func NewCassette(path string, mode CassetteMode) (*Cassette, error) {
+	c := &Cassette{Path: path, Mode: mode, served: make(map[string]int)}
+	if mode != CassetteReplay {
+		return c, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &c.interactions); err != nil {
+		return nil, fmt.Errorf("amazonmws: reading cassette %s: %v", path, err)
+	}
+
+	return c, nil
+}
+
+// Interactions returns what the cassette holds.
+func (c *Cassette) Interactions() []Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]Interaction(nil), c.interactions...)
+}
+
+// Middleware records or replays the requests sent through next.
+func (c *Cassette) Middleware(next RoundTripper) RoundTripper {
+	return RoundTripperFunc(func(req *Request) (*Response, error) {
+		if c.Mode == CassetteReplay {
+			return c.replay(req)
+		}
+
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			return resp, err
+		}
+		if err := c.record(req, resp); err != nil {
+			return nil, err
+		}
+
+		return resp, nil
+	})
+}
+
+func (c *Cassette) replay(req *Request) (*Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := cassetteKey(req.Action, req.Section, req.Params, string(req.Body))
+
+	var matches []int
+	for i, interaction := range c.interactions {
+		if interaction.key() == key {
+			matches = append(matches, i)
+		}
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("%w: %s %s", ErrCassetteMiss, req.Action, req.Section)
+	}
+
+	n := c.served[key]
+	if n >= len(matches) {
+		n = len(matches) - 1
+	}
+	c.served[key]++
+
+	resp := c.interactions[matches[n]].Response
+	return &resp, nil
+}
+
+func (c *Cassette) record(req *Request, resp *Response) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	params := RedactParams(req.Params)
+	delete(params, "Timestamp")
+	delete(params, "Signature")
+
+	c.interactions = append(c.interactions, Interaction{
+		Action:   req.Action,
+		Section:  req.Section,
+		Params:   params,
+		Body:     string(req.Body),
+		Response: *resp,
+	})
+
+	data, err := json.MarshalIndent(c.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(c.Path, append(data, '\n'), 0644)
+}
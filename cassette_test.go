@@ -0,0 +1,95 @@
+package amazonmws
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCassetteRecordAndReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cassette")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "status.json")
+
+	sent := 0
+	amazon := func(next RoundTripper) RoundTripper {
+		return RoundTripperFunc(func(req *Request) (*Response, error) {
+			sent++
+			return &Response{
+				StatusCode: 200,
+				Body:       `<GetServiceStatusResponse><GetServiceStatusResult><Status>GREEN</Status></GetServiceStatusResult></GetServiceStatusResponse>`,
+				Quota:      Quota{MwsQuotaMax: 2, MwsQuotaRemaining: 1},
+			}, nil
+		})
+	}
+
+	recorder, err := NewCassette(path, CassetteRecord)
+	assert.Nil(t, err)
+	api := AmazonMWSAPI{
+		AccessKey:  "AKIDRECORD",
+		SecretKey:  "record-secret",
+		AuthToken:  "amzn.mws.record",
+		Host:       "mws.amazonservices.com",
+		SellerId:   "A1SELLER",
+		Middleware: []Middleware{recorder.Middleware, amazon},
+	}
+	_, _, err = api.GetServiceStatus("/Sellers/2011-07-01")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Len(t, recorder.Interactions(), 1)
+
+	data, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(data), "AKIDRECORD")
+	assert.NotContains(t, string(data), "amzn.mws.record")
+	assert.NotContains(t, string(data), "record-secret")
+	assert.NotContains(t, string(data), "Timestamp")
+
+	player, err := NewCassette(path, CassetteReplay)
+	assert.Nil(t, err)
+	api = AmazonMWSAPI{
+		AccessKey:  "AKIDREPLAY",
+		SecretKey:  "replay-secret",
+		Host:       "mws.amazonservices.com",
+		SellerId:   "A1SELLER",
+		Middleware: []Middleware{player.Middleware},
+	}
+	status, quota, err := api.GetServiceStatus("/Sellers/2011-07-01")
+	assert.Nil(t, err)
+	assert.Equal(t, ServiceStatusGreen, status.Status)
+	assert.Equal(t, float64(1), quota.MwsQuotaRemaining)
+	assert.Equal(t, 1, sent)
+
+	api.SellerId = "A2OTHER"
+	_, _, err = api.GetServiceStatus("/Sellers/2011-07-01")
+	assert.True(t, errors.Is(err, ErrCassetteMiss))
+}
+
+func TestCassetteReplaysInOrder(t *testing.T) {
+	player := &Cassette{Mode: CassetteReplay, served: make(map[string]int)}
+	for _, body := range []string{"first", "second"} {
+		player.interactions = append(player.interactions, Interaction{
+			Action:   "ListOrdersByNextToken",
+			Section:  "/Orders/2013-09-01",
+			Params:   map[string]string{"NextToken": "t-1", "Timestamp": "2021-02-01T10:00:00Z"},
+			Response: Response{StatusCode: 200, Body: body},
+		})
+	}
+
+	var bodies []string
+	for i := 0; i < 3; i++ {
+		resp, err := player.Middleware(nil).RoundTrip(&Request{
+			Action:  "ListOrdersByNextToken",
+			Section: "/Orders/2013-09-01",
+			Params:  map[string]string{"NextToken": "t-1", "Timestamp": "2021-03-01T10:00:00Z", "Signature": "sig"},
+		})
+		assert.Nil(t, err)
+		bodies = append(bodies, resp.Body)
+	}
+
+	assert.Equal(t, []string{"first", "second", "second"}, bodies)
+}
@@ -0,0 +1,216 @@
+package amazonmws
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// QueueMessage is a message received from a Queue. ReceiptHandle identifies
+// this particular delivery and is what Delete acknowledges.
+type QueueMessage struct {
+	MessageId     string
+	ReceiptHandle string
+	Body          []byte
+}
+
+// Queue is the source a Consumer pulls notification messages from.
+// Messages that are received but never deleted must be delivered again.
+type Queue interface {
+	Receive(ctx context.Context, max int) ([]QueueMessage, error)
+	Delete(ctx context.Context, msg QueueMessage) error
+}
+
+// NotificationHandler processes a decoded notification. Returning an error
+// leaves the message on the queue for redelivery.
+type NotificationHandler func(ctx context.Context, n *Notification) error
+
+// Consumer pulls messages from a Queue, decodes them and dispatches them to
+// the handler registered for their NotificationType. A message is deleted
+// only after its handler succeeds, or once it is found not to decode at all.
+type Consumer struct {
+	Queue Queue
+
+	// BatchSize is the maximum number of messages requested per Receive.
+	BatchSize int
+
+	// PollInterval is how long Run waits after an empty Receive. After a
+	// failed Receive it waits twice as long as the last time, up to
+	// maxPollBackoff.
+	PollInterval time.Duration
+
+	// OnError, if set, is called for every message that could not be
+	// decoded, had no handler, or whose handler or deletion failed, and with
+	// a zero QueueMessage when Run fails to receive from the queue. Messages
+	// that cannot be decoded are deleted once OnError returns, so keep a copy
+	// of msg there to dead-letter it.
+	OnError func(msg QueueMessage, err error)
+
+	mu       sync.RWMutex
+	handlers map[string]NotificationHandler
+	fallback NotificationHandler
+}
+
+// NewConsumer returns a Consumer reading from q with default settings.
+func NewConsumer(q Queue) *Consumer {
+	return &Consumer{
+		Queue:        q,
+		BatchSize:    10,
+		PollInterval: time.Second,
+		handlers:     make(map[string]NotificationHandler),
+	}
+}
+
+// Handle registers h for notifications of the given type, such as
+// NotificationTypeAnyOfferChanged.
+func (c *Consumer) Handle(notificationType string, h NotificationHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.handlers == nil {
+		c.handlers = make(map[string]NotificationHandler)
+	}
+	c.handlers[notificationType] = h
+}
+
+// HandleDefault registers h for notification types without their own handler.
+func (c *Consumer) HandleDefault(h NotificationHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.fallback = h
+}
+
+func (c *Consumer) handler(notificationType string) NotificationHandler {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if h, ok := c.handlers[notificationType]; ok {
+		return h
+	}
+	return c.fallback
+}
+
+// maxPollBackoff caps how long Run waits between failing Receive calls.
+const maxPollBackoff = time.Minute
+
+// Run polls the queue until ctx is done.
+func (c *Consumer) Run(ctx context.Context) error {
+	failures := 0
+	for {
+		n, err := c.Poll(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		wait := c.PollInterval
+		switch {
+		case err != nil:
+			c.reportError(QueueMessage{}, err)
+			wait = pollBackoff(c.PollInterval, failures)
+			failures++
+		case n == 0:
+			failures = 0
+		default:
+			failures = 0
+			continue
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// pollBackoff returns how long to wait after failures consecutive failed
+// Receive calls, doubling interval each time.
+func pollBackoff(interval time.Duration, failures int) time.Duration {
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	wait := interval
+	for i := 0; i < failures && wait < maxPollBackoff; i++ {
+		wait *= 2
+	}
+	if wait > maxPollBackoff && interval < maxPollBackoff {
+		wait = maxPollBackoff
+	}
+
+	return wait
+}
+
+// Poll receives a single batch of messages and processes them, returning the
+// number of messages received.
+func (c *Consumer) Poll(ctx context.Context) (int, error) {
+	batchSize := c.BatchSize
+	if batchSize <= 0 {
+		batchSize = 10
+	}
+
+	messages, err := c.Queue.Receive(ctx, batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, msg := range messages {
+		if err := c.process(ctx, msg); err != nil {
+			c.reportError(msg, err)
+		}
+	}
+
+	return len(messages), nil
+}
+
+func (c *Consumer) process(ctx context.Context, msg QueueMessage) error {
+	n, err := DecodeNotification(unwrapSNS(msg.Body))
+	if err != nil {
+		// A message that does not decode now never will, so report it and
+		// drop it rather than have it redelivered forever.
+		c.reportError(msg, err)
+		return c.Queue.Delete(ctx, msg)
+	}
+
+	h := c.handler(n.NotificationMetaData.NotificationType)
+	if h == nil {
+		return fmt.Errorf("amazonmws: no handler for notification type %q", n.NotificationMetaData.NotificationType)
+	}
+
+	if err := h(ctx, n); err != nil {
+		return err
+	}
+
+	return c.Queue.Delete(ctx, msg)
+}
+
+func (c *Consumer) reportError(msg QueueMessage, err error) {
+	if c.OnError != nil {
+		c.OnError(msg, err)
+	}
+}
+
+// unwrapSNS returns the inner message when body is an SNS envelope, so that
+// notifications fanned out through SNS decode like those sent to SQS directly.
+func unwrapSNS(body []byte) []byte {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return body
+	}
+
+	var envelope struct {
+		Type    string `json:"Type"`
+		Message string `json:"Message"`
+	}
+	if err := json.Unmarshal(trimmed, &envelope); err != nil || envelope.Message == "" {
+		return body
+	}
+
+	return []byte(envelope.Message)
+}
@@ -0,0 +1,186 @@
+package amazonmws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"html"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func notificationMessage(notificationType string) []byte {
+	return []byte(`<Notification><NotificationMetaData><NotificationType>` + notificationType + `</NotificationType></NotificationMetaData><NotificationPayload/></Notification>`)
+}
+
+func TestConsumerDeletesOnlyAfterHandlerSucceeds(t *testing.T) {
+	queue := NewMemoryQueue()
+	queue.VisibilityTimeout = 0
+	queue.Send(notificationMessage(NotificationTypeAnyOfferChanged))
+	queue.Send(notificationMessage(NotificationTypeFeedProcessingFinished))
+	queue.Send(notificationMessage(NotificationTypeTest))
+	queue.Send([]byte(`{"Type":"Notification","Message":"<Notification><NotificationMetaData><NotificationType>AnyOfferChanged</NotificationType></NotificationMetaData></Notification>"}`))
+
+	consumer := NewConsumer(queue)
+
+	var offers int
+	consumer.Handle(NotificationTypeAnyOfferChanged, func(ctx context.Context, n *Notification) error {
+		offers++
+		return nil
+	})
+	consumer.Handle(NotificationTypeFeedProcessingFinished, func(ctx context.Context, n *Notification) error {
+		return errors.New("database unavailable")
+	})
+
+	var failed int
+	consumer.OnError = func(msg QueueMessage, err error) {
+		failed++
+	}
+
+	n, err := consumer.Poll(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 4, n)
+	assert.Equal(t, 2, offers)
+	assert.Equal(t, 2, failed)
+	assert.Equal(t, 2, queue.Len())
+
+	consumer.HandleDefault(func(ctx context.Context, n *Notification) error {
+		return nil
+	})
+	consumer.Handle(NotificationTypeFeedProcessingFinished, func(ctx context.Context, n *Notification) error {
+		return nil
+	})
+
+	_, err = consumer.Poll(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 0, queue.Len())
+}
+
+func TestConsumerDropsMessagesThatDoNotDecode(t *testing.T) {
+	queue := NewMemoryQueue()
+	queue.VisibilityTimeout = 0
+	queue.Send([]byte("not a notification"))
+
+	consumer := NewConsumer(queue)
+	consumer.HandleDefault(func(ctx context.Context, n *Notification) error {
+		return nil
+	})
+
+	var failed []QueueMessage
+	consumer.OnError = func(msg QueueMessage, err error) {
+		assert.Equal(t, 1, queue.Len())
+		failed = append(failed, msg)
+	}
+
+	n, err := consumer.Poll(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+	assert.Len(t, failed, 1)
+	assert.Equal(t, []byte("not a notification"), failed[0].Body)
+	assert.Equal(t, 0, queue.Len())
+}
+
+type failingQueue struct {
+	err      error
+	received []time.Time
+}
+
+func (q *failingQueue) Receive(ctx context.Context, max int) ([]QueueMessage, error) {
+	q.received = append(q.received, time.Now())
+	return nil, q.err
+}
+
+func (q *failingQueue) Delete(ctx context.Context, msg QueueMessage) error {
+	return nil
+}
+
+func TestConsumerRunReportsReceiveErrors(t *testing.T) {
+	denied := errors.New("access denied")
+	queue := &failingQueue{err: denied}
+	consumer := NewConsumer(queue)
+	consumer.PollInterval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var reported []error
+	consumer.OnError = func(msg QueueMessage, err error) {
+		assert.Equal(t, QueueMessage{}, msg)
+		reported = append(reported, err)
+		if len(reported) == 3 {
+			cancel()
+		}
+	}
+
+	assert.Equal(t, context.Canceled, consumer.Run(ctx))
+	assert.Equal(t, []error{denied, denied, denied}, reported)
+	assert.True(t, queue.received[2].Sub(queue.received[1]) >= 20*time.Millisecond)
+}
+
+func TestPollBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, pollBackoff(time.Second, 0))
+	assert.Equal(t, 4*time.Second, pollBackoff(time.Second, 2))
+	assert.Equal(t, maxPollBackoff, pollBackoff(time.Second, 10))
+	assert.Equal(t, 2*time.Minute, pollBackoff(2*time.Minute, 3))
+	assert.Equal(t, time.Second, pollBackoff(0, 0))
+}
+
+func TestMemoryQueueVisibilityTimeout(t *testing.T) {
+	queue := NewMemoryQueue()
+	queue.VisibilityTimeout = time.Hour
+	queue.Send([]byte("a"))
+
+	first, _ := queue.Receive(context.Background(), 10)
+	second, _ := queue.Receive(context.Background(), 10)
+
+	assert.Len(t, first, 1)
+	assert.Len(t, second, 0)
+	assert.Nil(t, queue.Delete(context.Background(), first[0]))
+	assert.Equal(t, ErrUnknownReceipt, queue.Delete(context.Background(), first[0]))
+}
+
+func TestSQSQueue(t *testing.T) {
+	deleted := make(chan string, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := ioutil.ReadAll(r.Body)
+		form, _ := url.ParseQuery(string(raw))
+
+		assert.Equal(t, "/000000000000/mws", r.URL.Path)
+		assert.Equal(t, "2012-11-05", form.Get("Version"))
+
+		switch form.Get("Action") {
+		case "ReceiveMessage":
+			assert.Equal(t, "10", form.Get("MaxNumberOfMessages"))
+			fmt.Fprintf(w, `<ReceiveMessageResponse><ReceiveMessageResult><Message><MessageId>m-1</MessageId><ReceiptHandle>r-1</ReceiptHandle><Body>%s</Body></Message></ReceiveMessageResult></ReceiveMessageResponse>`,
+				html.EscapeString(string(notificationMessage(NotificationTypeTest))))
+		case "DeleteMessage":
+			deleted <- form.Get("ReceiptHandle")
+			fmt.Fprint(w, `<DeleteMessageResponse/>`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `<ErrorResponse><Error><Type>Sender</Type><Code>InvalidAction</Code><Message>unsupported</Message></Error><RequestId>x</RequestId></ErrorResponse>`)
+		}
+	}))
+	defer server.Close()
+
+	queue := &SQSQueue{QueueURL: server.URL + "/000000000000/mws"}
+
+	consumer := NewConsumer(queue)
+	consumer.Handle(NotificationTypeTest, func(ctx context.Context, n *Notification) error {
+		return nil
+	})
+
+	n, err := consumer.Poll(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, "r-1", <-deleted)
+
+	err = queue.Send(context.Background(), []byte("x"))
+	assert.Equal(t, &MWSError{Type: "Sender", Code: "InvalidAction", Message: "unsupported", RequestId: "x"}, err)
+}
@@ -0,0 +1,198 @@
+package amazonmws
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNoCredentials is returned by a provider that has no credentials to
+// offer, which lets ChainCredentials move on to the next one.
+var ErrNoCredentials = errors.New("amazonmws: no credentials found")
+
+// Credentials are the secrets used to sign a request. AuthToken is only set
+// when calling on behalf of another seller.
+type Credentials struct {
+	AccessKey string `json:"access_key"`
+	SecretKey string `json:"secret_key"`
+	AuthToken string `json:"auth_token"`
+}
+
+// Retrieve returns c itself, so fixed Credentials can be used as a provider.
+func (c Credentials) Retrieve() (Credentials, error) {
+	return c, nil
+}
+
+// CredentialsProvider supplies the credentials for a request. When
+// AmazonMWSAPI.Credentials is set it is consulted every time a request is
+// signed, so keys can be rotated without rebuilding the client.
+type CredentialsProvider interface {
+	Retrieve() (Credentials, error)
+}
+
+// credentials returns the credentials to sign the next request with, taken
+// from api.Credentials when set and from the AccessKey, SecretKey and
+// AuthToken fields otherwise.
+func (api AmazonMWSAPI) credentials() (Credentials, error) {
+	if api.Credentials == nil {
+		return Credentials{AccessKey: api.AccessKey, SecretKey: api.SecretKey, AuthToken: api.AuthToken}, nil
+	}
+
+	return api.Credentials.Retrieve()
+}
+
+// EnvCredentials reads Prefix+"ACCESS_KEY", Prefix+"SECRET_KEY" and the
+// optional Prefix+"AUTH_TOKEN" from the environment.
+type EnvCredentials struct {
+	Prefix string
+}
+
+func (e EnvCredentials) Retrieve() (Credentials, error) {
+	creds := Credentials{
+		AccessKey: os.Getenv(e.Prefix + "ACCESS_KEY"),
+		SecretKey: os.Getenv(e.Prefix + "SECRET_KEY"),
+		AuthToken: os.Getenv(e.Prefix + "AUTH_TOKEN"),
+	}
+	if creds.AccessKey == "" || creds.SecretKey == "" {
+		return Credentials{}, fmt.Errorf("%w: %sACCESS_KEY and %sSECRET_KEY must be set", ErrNoCredentials, e.Prefix, e.Prefix)
+	}
+
+	return creds, nil
+}
+
+// FileCredentials reads credentials from a JSON file, or a YAML file when
+// Path ends in .yaml or .yml, with the keys access_key, secret_key and
+// auth_token. Only flat "key: value" YAML is understood. The file is read on
+// every call; wrap it in CachingCredentials to avoid that.
+type FileCredentials struct {
+	Path string
+}
+
+func (f FileCredentials) Retrieve() (Credentials, error) {
+	data, err := ioutil.ReadFile(f.Path)
+	if os.IsNotExist(err) {
+		return Credentials{}, fmt.Errorf("%w: %s does not exist", ErrNoCredentials, f.Path)
+	}
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	var creds Credentials
+	switch strings.ToLower(filepath.Ext(f.Path)) {
+	case ".yaml", ".yml":
+		creds, err = parseYAMLCredentials(data)
+	default:
+		err = json.Unmarshal(data, &creds)
+	}
+	if err != nil {
+		return Credentials{}, fmt.Errorf("amazonmws: reading %s: %v", f.Path, err)
+	}
+	if creds.AccessKey == "" || creds.SecretKey == "" {
+		return Credentials{}, fmt.Errorf("%w: %s has no access_key or secret_key", ErrNoCredentials, f.Path)
+	}
+
+	return creds, nil
+}
+
+func parseYAMLCredentials(data []byte) (Credentials, error) {
+	var creds Credentials
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || text == "---" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		i := strings.Index(text, ":")
+		if i < 0 {
+			return Credentials{}, fmt.Errorf("line %d: expected key: value", line)
+		}
+		key := strings.TrimSpace(text[:i])
+		value := strings.TrimSpace(text[i+1:])
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+
+		switch key {
+		case "access_key":
+			creds.AccessKey = value
+		case "secret_key":
+			creds.SecretKey = value
+		case "auth_token":
+			creds.AuthToken = value
+		}
+	}
+
+	return creds, scanner.Err()
+}
+
+// ChainCredentials tries each provider in turn and returns the first
+// credentials found. A provider failing with anything but ErrNoCredentials
+// stops the chain.
+type ChainCredentials []CredentialsProvider
+
+func (c ChainCredentials) Retrieve() (Credentials, error) {
+	for _, provider := range c {
+		creds, err := provider.Retrieve()
+		if err == nil {
+			return creds, nil
+		}
+		if !errors.Is(err, ErrNoCredentials) {
+			return Credentials{}, err
+		}
+	}
+
+	return Credentials{}, ErrNoCredentials
+}
+
+// CachingCredentials keeps the credentials of Provider for TTL before asking
+// it again. It is safe for concurrent use.
+type CachingCredentials struct {
+	Provider CredentialsProvider
+	TTL      time.Duration
+
+	mu      sync.Mutex
+	creds   Credentials
+	expires time.Time
+}
+
+func NewCachingCredentials(provider CredentialsProvider, ttl time.Duration) *CachingCredentials {
+	return &CachingCredentials{Provider: provider, TTL: ttl}
+}
+
+func (c *CachingCredentials) Retrieve() (Credentials, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if time.Now().Before(c.expires) {
+		return c.creds, nil
+	}
+
+	creds, err := c.Provider.Retrieve()
+	if err != nil {
+		return Credentials{}, err
+	}
+	c.creds = creds
+	c.expires = time.Now().Add(c.TTL)
+
+	return creds, nil
+}
+
+// Expire forces the next Retrieve to ask Provider again, for example after
+// Amazon rejected the cached keys.
+func (c *CachingCredentials) Expire() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.creds = Credentials{}
+	c.expires = time.Time{}
+}
@@ -0,0 +1,86 @@
+package amazonmws
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileCredentials(t *testing.T) {
+	dir, err := ioutil.TempDir("", "credentials")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	jsonPath := filepath.Join(dir, "credentials.json")
+	ioutil.WriteFile(jsonPath, []byte(`{"access_key": "AKIDJSON", "secret_key": "json-secret"}`), 0600)
+
+	yamlPath := filepath.Join(dir, "credentials.yaml")
+	ioutil.WriteFile(yamlPath, []byte("# rotated nightly\naccess_key: AKIDYAML\nsecret_key: \"yaml: secret\"\nauth_token: 'amzn.mws.token'\n"), 0600)
+
+	creds, err := FileCredentials{Path: jsonPath}.Retrieve()
+	assert.Nil(t, err)
+	assert.Equal(t, Credentials{AccessKey: "AKIDJSON", SecretKey: "json-secret"}, creds)
+
+	creds, err = FileCredentials{Path: yamlPath}.Retrieve()
+	assert.Nil(t, err)
+	assert.Equal(t, Credentials{AccessKey: "AKIDYAML", SecretKey: "yaml: secret", AuthToken: "amzn.mws.token"}, creds)
+
+	_, err = FileCredentials{Path: filepath.Join(dir, "missing.json")}.Retrieve()
+	assert.True(t, errors.Is(err, ErrNoCredentials))
+}
+
+func TestChainCredentials(t *testing.T) {
+	os.Setenv("CHAIN_TEST_ACCESS_KEY", "AKIDENV")
+	os.Setenv("CHAIN_TEST_SECRET_KEY", "env-secret")
+	defer os.Unsetenv("CHAIN_TEST_ACCESS_KEY")
+	defer os.Unsetenv("CHAIN_TEST_SECRET_KEY")
+
+	chain := ChainCredentials{
+		FileCredentials{Path: "does-not-exist.json"},
+		EnvCredentials{Prefix: "UNSET_TEST_"},
+		EnvCredentials{Prefix: "CHAIN_TEST_"},
+	}
+
+	creds, err := chain.Retrieve()
+	assert.Nil(t, err)
+	assert.Equal(t, "AKIDENV", creds.AccessKey)
+
+	_, err = ChainCredentials{EnvCredentials{Prefix: "UNSET_TEST_"}}.Retrieve()
+	assert.Equal(t, ErrNoCredentials, err)
+}
+
+type countingProvider struct {
+	calls int
+}
+
+func (p *countingProvider) Retrieve() (Credentials, error) {
+	p.calls++
+	return Credentials{AccessKey: "AKID", SecretKey: "secret"}, nil
+}
+
+func TestCachingCredentials(t *testing.T) {
+	provider := &countingProvider{}
+	cache := NewCachingCredentials(provider, time.Hour)
+
+	cache.Retrieve()
+	cache.Retrieve()
+	assert.Equal(t, 1, provider.calls)
+
+	cache.Expire()
+	cache.Retrieve()
+	assert.Equal(t, 2, provider.calls)
+}
+
+func TestSignUsesCredentialsProvider(t *testing.T) {
+	provider := &countingProvider{}
+	api := AmazonMWSAPI{Host: "mws.amazonservices.com", Credentials: provider}
+
+	signed, err := api.PresignURL("GetServiceStatus", "/Products/2011-10-01", nil, 0)
+	assert.Nil(t, err)
+	assert.Contains(t, signed, "AWSAccessKeyId=AKID&")
+	assert.Equal(t, 1, provider.calls)
+}
@@ -0,0 +1,181 @@
+package amazonmws
+
+import (
+	"strconv"
+	"time"
+)
+
+// EasyShipDimensions is the size of an Easy Ship package. Identifier names a
+// predefined package type and may be left empty for custom boxes.
+type EasyShipDimensions struct {
+	Length     string `xml:"Length"`
+	Width      string `xml:"Width"`
+	Height     string `xml:"Height"`
+	Unit       string `xml:"Unit"`
+	Identifier string `xml:"Identifier"`
+}
+
+func (d EasyShipDimensions) appendQuery(params map[string]string, prefix string) {
+	params[prefix+".Length"] = d.Length
+	params[prefix+".Width"] = d.Width
+	params[prefix+".Height"] = d.Height
+	params[prefix+".Unit"] = d.Unit
+	if d.Identifier != "" {
+		params[prefix+".Identifier"] = d.Identifier
+	}
+}
+
+type PickupSlot struct {
+	SlotId          string    `xml:"SlotId"`
+	PickupTimeStart time.Time `xml:"PickupTimeStart"`
+	PickupTimeEnd   time.Time `xml:"PickupTimeEnd"`
+}
+
+// appendQuery only sends SlotId, which is all Amazon needs to book a slot
+// returned by ListPickupSlots.
+func (s PickupSlot) appendQuery(params map[string]string, prefix string) {
+	params[prefix+".SlotId"] = s.SlotId
+}
+
+type EasyShipItem struct {
+	OrderItemId               string   `xml:"OrderItemId"`
+	OrderItemSerialNumberList []string `xml:"OrderItemSerialNumberList>OrderItemSerialNumber"`
+}
+
+func (item EasyShipItem) appendQuery(params map[string]string, prefix string) {
+	params[prefix+".OrderItemId"] = item.OrderItemId
+	for i, serial := range item.OrderItemSerialNumberList {
+		params[prefix+".OrderItemSerialNumberList.OrderItemSerialNumber."+strconv.Itoa(i+1)] = serial
+	}
+}
+
+type ScheduledPackageId struct {
+	AmazonOrderId string `xml:"AmazonOrderId"`
+	PackageId     string `xml:"PackageId"`
+}
+
+func (id ScheduledPackageId) appendQuery(params map[string]string, prefix string) {
+	params[prefix+".AmazonOrderId"] = id.AmazonOrderId
+	if id.PackageId != "" {
+		params[prefix+".PackageId"] = id.PackageId
+	}
+}
+
+type EasyShipInvoice struct {
+	InvoiceNumber string    `xml:"InvoiceNumber"`
+	InvoiceDate   time.Time `xml:"InvoiceDate"`
+}
+
+type EasyShipPackage struct {
+	ScheduledPackageId ScheduledPackageId `xml:"ScheduledPackageId"`
+	PackageDimensions  EasyShipDimensions `xml:"PackageDimensions"`
+	PackageWeight      Weight             `xml:"PackageWeight"`
+	PackageItemList    []EasyShipItem     `xml:"PackageItemList>Item"`
+	PackagePickupSlot  PickupSlot         `xml:"PackagePickupSlot"`
+	PackageIdentifier  string             `xml:"PackageIdentifier"`
+	Invoice            *EasyShipInvoice   `xml:"Invoice"`
+	TrackingId         string             `xml:"TrackingDetails>TrackingId"`
+	PackageStatus      string             `xml:"PackageStatus"`
+}
+
+type listPickupSlotsResponse struct {
+	PickupSlots      []PickupSlot     `xml:"ListPickupSlotsResult>PickupSlotList>PickupSlot"`
+	ResponseMetadata ResponseMetadata `xml:"ResponseMetadata"`
+}
+
+type createScheduledPackageResponse struct {
+	Package          EasyShipPackage  `xml:"CreateScheduledPackageResult>ScheduledPackage"`
+	ResponseMetadata ResponseMetadata `xml:"ResponseMetadata"`
+}
+
+type updateScheduledPackagesResponse struct {
+	Packages         []EasyShipPackage `xml:"UpdateScheduledPackagesResult>ScheduledPackageList>ScheduledPackage"`
+	ResponseMetadata ResponseMetadata  `xml:"ResponseMetadata"`
+}
+
+type getScheduledPackageResponse struct {
+	Package          EasyShipPackage  `xml:"GetScheduledPackageResult>ScheduledPackage"`
+	ResponseMetadata ResponseMetadata `xml:"ResponseMetadata"`
+}
+
+// ListPickupSlots returns the carrier pickup windows available for a package
+// of the given size and weight from an Easy Ship order.
+func (api AmazonMWSAPI) ListPickupSlots(amazonOrderId string, dimensions EasyShipDimensions, weight Weight) ([]PickupSlot, Quota, error) {
+	params := make(map[string]string)
+
+	params["MarketplaceId"] = api.MarketplaceId
+	params["AmazonOrderId"] = amazonOrderId
+	dimensions.appendQuery(params, "PackageDimensions")
+	weight.appendQuery(params, "PackageWeight")
+
+	var resp listPickupSlotsResponse
+	quota, err := api.fetchAndDecode("ListPickupSlots", "/EasyShip/2018-09-01", params, nil, &resp)
+
+	return resp.PickupSlots, quota, err
+}
+
+type CreateScheduledPackageRequest struct {
+	AmazonOrderId     string
+	PackageDimensions EasyShipDimensions
+	PackageWeight     Weight
+	PackageItemList   []EasyShipItem
+	PackagePickupSlot PickupSlot
+	PackageIdentifier string
+}
+
+func (api AmazonMWSAPI) CreateScheduledPackage(req CreateScheduledPackageRequest) (EasyShipPackage, Quota, error) {
+	params := make(map[string]string)
+
+	params["MarketplaceId"] = api.MarketplaceId
+	params["ScheduledPackageCreateRequest.AmazonOrderId"] = req.AmazonOrderId
+
+	prefix := "ScheduledPackageCreateRequest.PackageRequestDetails"
+	req.PackageDimensions.appendQuery(params, prefix+".PackageDimensions")
+	req.PackageWeight.appendQuery(params, prefix+".PackageWeight")
+	for i, item := range req.PackageItemList {
+		item.appendQuery(params, prefix+".PackageItemList.Item."+strconv.Itoa(i+1))
+	}
+	req.PackagePickupSlot.appendQuery(params, prefix+".PackagePickupSlot")
+	if req.PackageIdentifier != "" {
+		params[prefix+".PackageIdentifier"] = req.PackageIdentifier
+	}
+
+	var resp createScheduledPackageResponse
+	quota, err := api.fetchAndDecode("CreateScheduledPackage", "/EasyShip/2018-09-01", params, nil, &resp)
+
+	return resp.Package, quota, err
+}
+
+// ScheduledPackageUpdate moves an existing package to a new pickup slot.
+type ScheduledPackageUpdate struct {
+	ScheduledPackageId ScheduledPackageId
+	PackagePickupSlot  PickupSlot
+}
+
+func (api AmazonMWSAPI) UpdateScheduledPackages(updates []ScheduledPackageUpdate) ([]EasyShipPackage, Quota, error) {
+	params := make(map[string]string)
+
+	params["MarketplaceId"] = api.MarketplaceId
+	for i, update := range updates {
+		prefix := "ScheduledPackageUpdateRequestList.PackageUpdateDetails." + strconv.Itoa(i+1)
+		update.ScheduledPackageId.appendQuery(params, prefix+".ScheduledPackageId")
+		update.PackagePickupSlot.appendQuery(params, prefix+".PackagePickupSlot")
+	}
+
+	var resp updateScheduledPackagesResponse
+	quota, err := api.fetchAndDecode("UpdateScheduledPackages", "/EasyShip/2018-09-01", params, nil, &resp)
+
+	return resp.Packages, quota, err
+}
+
+func (api AmazonMWSAPI) GetScheduledPackage(id ScheduledPackageId) (EasyShipPackage, Quota, error) {
+	params := make(map[string]string)
+
+	params["MarketplaceId"] = api.MarketplaceId
+	id.appendQuery(params, "ScheduledPackageId")
+
+	var resp getScheduledPackageResponse
+	quota, err := api.fetchAndDecode("GetScheduledPackage", "/EasyShip/2018-09-01", params, nil, &resp)
+
+	return resp.Package, quota, err
+}
@@ -0,0 +1,53 @@
+package amazonmws
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEasyShipItemQuery(t *testing.T) {
+	params := make(map[string]string)
+
+	prefix := "ScheduledPackageCreateRequest.PackageRequestDetails"
+	EasyShipDimensions{Length: "10", Width: "8", Height: "4", Unit: "Cm"}.appendQuery(params, prefix+".PackageDimensions")
+	EasyShipItem{OrderItemId: "item-1", OrderItemSerialNumberList: []string{"SN-1", "SN-2"}}.appendQuery(params, prefix+".PackageItemList.Item.1")
+
+	assert.Equal(t, "10", params[prefix+".PackageDimensions.Length"])
+	assert.Equal(t, "item-1", params[prefix+".PackageItemList.Item.1.OrderItemId"])
+	assert.Equal(t, "SN-2", params[prefix+".PackageItemList.Item.1.OrderItemSerialNumberList.OrderItemSerialNumber.2"])
+	_, hasIdentifier := params[prefix+".PackageDimensions.Identifier"]
+	assert.False(t, hasIdentifier)
+}
+
+func TestDecodeGetScheduledPackage(t *testing.T) {
+	body := `<GetScheduledPackageResponse xmlns="https://mws.amazonservices.in/EasyShip/2018-09-01">
+  <GetScheduledPackageResult>
+    <ScheduledPackage>
+      <ScheduledPackageId><AmazonOrderId>403-1234567-1234567</AmazonOrderId><PackageId>1</PackageId></ScheduledPackageId>
+      <PackageDimensions><Length>10</Length><Width>8</Width><Height>4</Height><Unit>Cm</Unit></PackageDimensions>
+      <PackageWeight><Value>500</Value><Unit>g</Unit></PackageWeight>
+      <PackageItemList>
+        <Item><OrderItemId>item-1</OrderItemId><OrderItemSerialNumberList><OrderItemSerialNumber>SN-1</OrderItemSerialNumber></OrderItemSerialNumberList></Item>
+      </PackageItemList>
+      <PackagePickupSlot><SlotId>slot-1</SlotId><PickupTimeStart>2019-01-02T10:00:00Z</PickupTimeStart><PickupTimeEnd>2019-01-02T13:00:00Z</PickupTimeEnd></PackagePickupSlot>
+      <Invoice><InvoiceNumber>INV-1</InvoiceNumber><InvoiceDate>2019-01-01T08:00:00Z</InvoiceDate></Invoice>
+      <TrackingDetails><TrackingId>TRK-1</TrackingId></TrackingDetails>
+      <PackageStatus>PendingSchedule</PackageStatus>
+    </ScheduledPackage>
+  </GetScheduledPackageResult>
+  <ResponseMetadata><RequestId>6a1f8b7e-2a36-4b62-a1a1-3c4a2f8f0d11</RequestId></ResponseMetadata>
+</GetScheduledPackageResponse>`
+
+	var resp getScheduledPackageResponse
+	err := decodeResponse(body, &resp)
+	assert.Nil(t, err)
+
+	pkg := resp.Package
+	assert.Equal(t, "403-1234567-1234567", pkg.ScheduledPackageId.AmazonOrderId)
+	assert.Equal(t, Weight{Unit: "g", Value: "500"}, pkg.PackageWeight)
+	assert.Equal(t, []string{"SN-1"}, pkg.PackageItemList[0].OrderItemSerialNumberList)
+	assert.Equal(t, "slot-1", pkg.PackagePickupSlot.SlotId)
+	assert.Equal(t, 3, pkg.PackagePickupSlot.PickupTimeEnd.Hour()-pkg.PackagePickupSlot.PickupTimeStart.Hour())
+	assert.Equal(t, "INV-1", pkg.Invoice.InvoiceNumber)
+	assert.Equal(t, "TRK-1", pkg.TrackingId)
+}
@@ -0,0 +1,66 @@
+package amazonmws
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnknownSection is returned for a request to an API section that has no
+// version registered; see RegisterSection.
+var ErrUnknownSection = errors.New("amazonmws: unknown API section")
+
+func unknownSectionError(section string) error {
+	return fmt.Errorf("%w %s", ErrUnknownSection, section)
+}
+
+// MWSError is an error reported by MWS, either for a whole request
+// (an ErrorResponse document) or for a single identifier in a list operation.
+type MWSError struct {
+	Type      string `xml:"Type"`
+	Code      string `xml:"Code"`
+	Message   string `xml:"Message"`
+	RequestId string `xml:"-"`
+}
+
+func (e *MWSError) Error() string {
+	if e.Code == "" {
+		return "amazonmws: " + e.Message
+	}
+	return fmt.Sprintf("amazonmws: %s: %s", e.Code, e.Message)
+}
+
+// IsThrottled reports whether Amazon rejected the request because the quota
+// for the action was exhausted.
+func (e *MWSError) IsThrottled() bool {
+	return e.Code == "RequestThrottled" || e.Code == "QuotaExceeded"
+}
+
+type errorResponse struct {
+	XMLName   xml.Name `xml:"ErrorResponse"`
+	Error     MWSError `xml:"Error"`
+	RequestId string   `xml:"RequestId"`
+	RequestID string   `xml:"RequestID"`
+}
+
+// parseErrorResponse returns the error described by body when it is an MWS
+// ErrorResponse document, and nil otherwise.
+func parseErrorResponse(body string) *MWSError {
+	if !strings.Contains(body, "<ErrorResponse") {
+		return nil
+	}
+
+	var resp errorResponse
+	if err := xml.Unmarshal([]byte(body), &resp); err != nil {
+		return nil
+	}
+
+	e := resp.Error
+	e.RequestId = resp.RequestId
+	if e.RequestId == "" {
+		e.RequestId = resp.RequestID
+	}
+
+	return &e
+}
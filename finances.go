@@ -0,0 +1,704 @@
+package amazonmws
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Money is a currency amount as reported by the Finances API. The amount is
+// kept in its decimal string form so that no precision is lost to floats.
+type Money struct {
+	CurrencyCode   string `xml:"CurrencyCode"`
+	CurrencyAmount string `xml:"CurrencyAmount"`
+}
+
+// Rat returns the exact value of the amount.
+func (m Money) Rat() (*big.Rat, error) {
+	if m.CurrencyAmount == "" {
+		return new(big.Rat), nil
+	}
+
+	r, ok := new(big.Rat).SetString(m.CurrencyAmount)
+	if !ok {
+		return nil, fmt.Errorf("amazonmws: invalid currency amount %q", m.CurrencyAmount)
+	}
+
+	return r, nil
+}
+
+// Add returns the sum of m and o. Amounts in different currencies cannot be
+// added; a zero Money adopts the currency of the other operand.
+func (m Money) Add(o Money) (Money, error) {
+	code := m.CurrencyCode
+	switch {
+	case code == "":
+		code = o.CurrencyCode
+	case o.CurrencyCode != "" && o.CurrencyCode != code:
+		return Money{}, fmt.Errorf("amazonmws: cannot add %s to %s", o.CurrencyCode, code)
+	}
+
+	a, err := m.Rat()
+	if err != nil {
+		return Money{}, err
+	}
+	b, err := o.Rat()
+	if err != nil {
+		return Money{}, err
+	}
+
+	scale := decimalPlaces(m.CurrencyAmount)
+	if s := decimalPlaces(o.CurrencyAmount); s > scale {
+		scale = s
+	}
+
+	return Money{
+		CurrencyCode:   code,
+		CurrencyAmount: new(big.Rat).Add(a, b).FloatString(scale),
+	}, nil
+}
+
+// MinorUnits returns the amount in the currency's minor unit, such as cents
+// for USD or yen for JPY, and fails if the amount carries more precision than
+// that.
+func (m Money) MinorUnits() (int64, error) {
+	r, err := m.Rat()
+	if err != nil {
+		return 0, err
+	}
+
+	exp := minorUnitExponent(m.CurrencyCode)
+	for i := 0; i < exp; i++ {
+		r.Mul(r, big.NewRat(10, 1))
+	}
+	if !r.IsInt() {
+		return 0, fmt.Errorf("amazonmws: currency amount %q has more than %d decimal places for %s", m.CurrencyAmount, exp, m.CurrencyCode)
+	}
+
+	return r.Num().Int64(), nil
+}
+
+// minorUnitExponents lists the ISO 4217 currencies whose minor unit is not a
+// hundredth of the major unit.
+var minorUnitExponents = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0,
+	"KRW": 0, "PYG": 0, "RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0,
+	"XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+func minorUnitExponent(currencyCode string) int {
+	if exp, ok := minorUnitExponents[strings.ToUpper(currencyCode)]; ok {
+		return exp
+	}
+	return 2
+}
+
+// Time is a timestamp in a Finances response. Amazon sometimes sends an empty
+// element, such as <PostedDate/>, which decodes to the zero time instead of
+// failing the whole page.
+type Time struct {
+	time.Time
+}
+
+func (t *Time) UnmarshalText(text []byte) error {
+	if len(strings.TrimSpace(string(text))) == 0 {
+		t.Time = time.Time{}
+		return nil
+	}
+
+	return t.Time.UnmarshalText(text)
+}
+
+func decimalPlaces(amount string) int {
+	i := strings.IndexByte(amount, '.')
+	if i < 0 {
+		return 0
+	}
+	return len(amount) - i - 1
+}
+
+type ChargeComponent struct {
+	ChargeType   string `xml:"ChargeType"`
+	ChargeAmount Money  `xml:"ChargeAmount"`
+}
+
+type FeeComponent struct {
+	FeeType   string `xml:"FeeType"`
+	FeeAmount Money  `xml:"FeeAmount"`
+}
+
+type DirectPayment struct {
+	DirectPaymentType   string `xml:"DirectPaymentType"`
+	DirectPaymentAmount Money  `xml:"DirectPaymentAmount"`
+}
+
+type Promotion struct {
+	PromotionType   string `xml:"PromotionType"`
+	PromotionId     string `xml:"PromotionId"`
+	PromotionAmount Money  `xml:"PromotionAmount"`
+}
+
+type TaxWithheldComponent struct {
+	TaxCollectionModel string            `xml:"TaxCollectionModel"`
+	TaxesWithheld      []ChargeComponent `xml:"TaxesWithheld>ChargeComponent"`
+}
+
+type ShipmentItem struct {
+	SellerSKU                string                 `xml:"SellerSKU"`
+	OrderItemId              string                 `xml:"OrderItemId"`
+	OrderAdjustmentItemId    string                 `xml:"OrderAdjustmentItemId"`
+	QuantityShipped          int                    `xml:"QuantityShipped"`
+	ItemChargeList           []ChargeComponent      `xml:"ItemChargeList>ChargeComponent"`
+	ItemChargeAdjustmentList []ChargeComponent      `xml:"ItemChargeAdjustmentList>ChargeComponent"`
+	ItemFeeList              []FeeComponent         `xml:"ItemFeeList>FeeComponent"`
+	ItemFeeAdjustmentList    []FeeComponent         `xml:"ItemFeeAdjustmentList>FeeComponent"`
+	ItemTaxWithheldList      []TaxWithheldComponent `xml:"ItemTaxWithheldList>TaxWithheldComponent"`
+	PromotionList            []Promotion            `xml:"PromotionList>Promotion"`
+	PromotionAdjustmentList  []Promotion            `xml:"PromotionAdjustmentList>Promotion"`
+	CostOfPointsGranted      *Money                 `xml:"CostOfPointsGranted"`
+	CostOfPointsReturned     *Money                 `xml:"CostOfPointsReturned"`
+}
+
+// ShipmentEvent is a shipment, refund, guarantee claim or chargeback event.
+type ShipmentEvent struct {
+	AmazonOrderId              string            `xml:"AmazonOrderId"`
+	SellerOrderId              string            `xml:"SellerOrderId"`
+	MarketplaceName            string            `xml:"MarketplaceName"`
+	OrderChargeList            []ChargeComponent `xml:"OrderChargeList>ChargeComponent"`
+	OrderChargeAdjustmentList  []ChargeComponent `xml:"OrderChargeAdjustmentList>ChargeComponent"`
+	ShipmentFeeList            []FeeComponent    `xml:"ShipmentFeeList>FeeComponent"`
+	ShipmentFeeAdjustmentList  []FeeComponent    `xml:"ShipmentFeeAdjustmentList>FeeComponent"`
+	OrderFeeList               []FeeComponent    `xml:"OrderFeeList>FeeComponent"`
+	OrderFeeAdjustmentList     []FeeComponent    `xml:"OrderFeeAdjustmentList>FeeComponent"`
+	DirectPaymentList          []DirectPayment   `xml:"DirectPaymentList>DirectPayment"`
+	PostedDate                 Time              `xml:"PostedDate"`
+	ShipmentItemList           []ShipmentItem    `xml:"ShipmentItemList>ShipmentItem"`
+	ShipmentItemAdjustmentList []ShipmentItem    `xml:"ShipmentItemAdjustmentList>ShipmentItem"`
+}
+
+// RefundEvent, GuaranteeClaimEvent and ChargebackEvent share the layout of a
+// ShipmentEvent but are reported in their own lists.
+type (
+	RefundEvent         ShipmentEvent
+	GuaranteeClaimEvent ShipmentEvent
+	ChargebackEvent     ShipmentEvent
+)
+
+type PayWithAmazonEvent struct {
+	SellerOrderId         string          `xml:"SellerOrderId"`
+	TransactionPostedDate Time            `xml:"TransactionPostedDate"`
+	BusinessObjectType    string          `xml:"BusinessObjectType"`
+	SalesChannel          string          `xml:"SalesChannel"`
+	Charge                ChargeComponent `xml:"Charge"`
+	FeeList               []FeeComponent  `xml:"FeeList>FeeComponent"`
+	PaymentAmountType     string          `xml:"PaymentAmountType"`
+	AmountDescription     string          `xml:"AmountDescription"`
+	FulfillmentChannel    string          `xml:"FulfillmentChannel"`
+	StoreName             string          `xml:"StoreName"`
+}
+
+type SolutionProviderCreditEvent struct {
+	ProviderTransactionType string `xml:"ProviderTransactionType"`
+	SellerOrderId           string `xml:"SellerOrderId"`
+	MarketplaceId           string `xml:"MarketplaceId"`
+	MarketplaceCountryCode  string `xml:"MarketplaceCountryCode"`
+	SellerId                string `xml:"SellerId"`
+	SellerStoreName         string `xml:"SellerStoreName"`
+	ProviderId              string `xml:"ProviderId"`
+	ProviderStoreName       string `xml:"ProviderStoreName"`
+	TransactionAmount       Money  `xml:"TransactionAmount"`
+	TransactionCreationDate Time   `xml:"TransactionCreationDate"`
+}
+
+type RetrochargeEvent struct {
+	RetrochargeEventType                string                 `xml:"RetrochargeEventType"`
+	AmazonOrderId                       string                 `xml:"AmazonOrderId"`
+	PostedDate                          Time                   `xml:"PostedDate"`
+	BaseTax                             Money                  `xml:"BaseTax"`
+	ShippingTax                         Money                  `xml:"ShippingTax"`
+	MarketplaceName                     string                 `xml:"MarketplaceName"`
+	RetrochargeTaxWithheldComponentList []TaxWithheldComponent `xml:"RetrochargeTaxWithheldComponentList>TaxWithheldComponent"`
+}
+
+type RentalTransactionEvent struct {
+	AmazonOrderId         string                 `xml:"AmazonOrderId"`
+	RentalEventType       string                 `xml:"RentalEventType"`
+	ExtensionLength       int                    `xml:"ExtensionLength"`
+	PostedDate            Time                   `xml:"PostedDate"`
+	RentalChargeList      []ChargeComponent      `xml:"RentalChargeList>ChargeComponent"`
+	RentalFeeList         []FeeComponent         `xml:"RentalFeeList>FeeComponent"`
+	MarketplaceName       string                 `xml:"MarketplaceName"`
+	RentalInitialValue    Money                  `xml:"RentalInitialValue"`
+	RentalReimbursement   Money                  `xml:"RentalReimbursement"`
+	RentalTaxWithheldList []TaxWithheldComponent `xml:"RentalTaxWithheldList>TaxWithheldComponent"`
+}
+
+type PerformanceBondRefundEvent struct {
+	MarketplaceCountryCode string   `xml:"MarketplaceCountryCode"`
+	Amount                 Money    `xml:"Amount"`
+	ProductGroupList       []string `xml:"ProductGroupList>ProductGroup"`
+}
+
+type ProductAdsPaymentEvent struct {
+	PostedDate       Time   `xml:"postedDate"`
+	TransactionType  string `xml:"transactionType"`
+	InvoiceId        string `xml:"invoiceId"`
+	BaseValue        Money  `xml:"baseValue"`
+	TaxValue         Money  `xml:"taxValue"`
+	TransactionValue Money  `xml:"transactionValue"`
+}
+
+type ServiceFeeEvent struct {
+	AmazonOrderId  string         `xml:"AmazonOrderId"`
+	FeeReason      string         `xml:"FeeReason"`
+	FeeList        []FeeComponent `xml:"FeeList>FeeComponent"`
+	SellerSKU      string         `xml:"SellerSKU"`
+	FnSKU          string         `xml:"FnSKU"`
+	FeeDescription string         `xml:"FeeDescription"`
+	ASIN           string         `xml:"ASIN"`
+}
+
+type DebtRecoveryItem struct {
+	RecoveryAmount Money `xml:"RecoveryAmount"`
+	OriginalAmount Money `xml:"OriginalAmount"`
+	GroupBeginDate Time  `xml:"GroupBeginDate"`
+	GroupEndDate   Time  `xml:"GroupEndDate"`
+}
+
+type ChargeInstrument struct {
+	Description string `xml:"Description"`
+	Tail        string `xml:"Tail"`
+	Amount      Money  `xml:"Amount"`
+}
+
+type DebtRecoveryEvent struct {
+	DebtRecoveryType     string             `xml:"DebtRecoveryType"`
+	RecoveryAmount       Money              `xml:"RecoveryAmount"`
+	OverPaymentCredit    Money              `xml:"OverPaymentCredit"`
+	DebtRecoveryItemList []DebtRecoveryItem `xml:"DebtRecoveryItemList>DebtRecoveryItem"`
+	ChargeInstrumentList []ChargeInstrument `xml:"ChargeInstrumentList>ChargeInstrument"`
+}
+
+type LoanServicingEvent struct {
+	LoanAmount              Money  `xml:"LoanAmount"`
+	SourceBusinessEventType string `xml:"SourceBusinessEventType"`
+}
+
+type AdjustmentItem struct {
+	Quantity           string `xml:"Quantity"`
+	PerUnitAmount      Money  `xml:"PerUnitAmount"`
+	TotalAmount        Money  `xml:"TotalAmount"`
+	SellerSKU          string `xml:"SellerSKU"`
+	FnSKU              string `xml:"FnSKU"`
+	ProductDescription string `xml:"ProductDescription"`
+	ASIN               string `xml:"ASIN"`
+}
+
+type AdjustmentEvent struct {
+	AdjustmentType     string           `xml:"AdjustmentType"`
+	AdjustmentAmount   Money            `xml:"AdjustmentAmount"`
+	AdjustmentItemList []AdjustmentItem `xml:"AdjustmentItemList>AdjustmentItem"`
+	PostedDate         Time             `xml:"PostedDate"`
+}
+
+type CouponPaymentEvent struct {
+	PostedDate              Time            `xml:"PostedDate"`
+	CouponId                string          `xml:"CouponId"`
+	SellerCouponDescription string          `xml:"SellerCouponDescription"`
+	ClipOrRedemptionCount   int             `xml:"ClipOrRedemptionCount"`
+	PaymentEventId          string          `xml:"PaymentEventId"`
+	FeeComponent            FeeComponent    `xml:"FeeComponent"`
+	ChargeComponent         ChargeComponent `xml:"ChargeComponent"`
+	TotalAmount             Money           `xml:"TotalAmount"`
+}
+
+type SAFETReimbursementItem struct {
+	ItemChargeList     []ChargeComponent `xml:"itemChargeList>ChargeComponent"`
+	ProductDescription string            `xml:"productDescription"`
+	Quantity           string            `xml:"quantity"`
+}
+
+type SAFETReimbursementEvent struct {
+	PostedDate                 Time                     `xml:"PostedDate"`
+	SAFETClaimId               string                   `xml:"SAFETClaimId"`
+	ReimbursedAmount           Money                    `xml:"ReimbursedAmount"`
+	ReasonCode                 string                   `xml:"ReasonCode"`
+	SAFETReimbursementItemList []SAFETReimbursementItem `xml:"SAFETReimbursementItemList>SAFETReimbursementItem"`
+}
+
+type SellerReviewEnrollmentPaymentEvent struct {
+	PostedDate      Time            `xml:"PostedDate"`
+	EnrollmentId    string          `xml:"EnrollmentId"`
+	ParentASIN      string          `xml:"ParentASIN"`
+	FeeComponent    FeeComponent    `xml:"FeeComponent"`
+	ChargeComponent ChargeComponent `xml:"ChargeComponent"`
+	TotalAmount     Money           `xml:"TotalAmount"`
+}
+
+type FBALiquidationEvent struct {
+	PostedDate                Time   `xml:"PostedDate"`
+	OriginalRemovalOrderId    string `xml:"OriginalRemovalOrderId"`
+	LiquidationProceedsAmount Money  `xml:"LiquidationProceedsAmount"`
+	LiquidationFeeAmount      Money  `xml:"LiquidationFeeAmount"`
+}
+
+type ImagingServicesFeeEvent struct {
+	ImagingRequestBillingItemID string         `xml:"ImagingRequestBillingItemID"`
+	ASIN                        string         `xml:"ASIN"`
+	PostedDate                  Time           `xml:"PostedDate"`
+	FeeList                     []FeeComponent `xml:"FeeList>FeeComponent"`
+}
+
+type AffordabilityExpenseEvent struct {
+	AmazonOrderId   string `xml:"AmazonOrderId"`
+	PostedDate      Time   `xml:"PostedDate"`
+	MarketplaceId   string `xml:"MarketplaceId"`
+	TransactionType string `xml:"TransactionType"`
+	BaseExpense     Money  `xml:"BaseExpense"`
+	TaxTypeCGST     Money  `xml:"TaxTypeCGST"`
+	TaxTypeSGST     Money  `xml:"TaxTypeSGST"`
+	TaxTypeIGST     Money  `xml:"TaxTypeIGST"`
+	TotalExpense    Money  `xml:"TotalExpense"`
+}
+
+// AffordabilityExpenseReversalEvent shares the layout of an
+// AffordabilityExpenseEvent.
+type AffordabilityExpenseReversalEvent AffordabilityExpenseEvent
+
+type NetworkComminglingTransactionEvent struct {
+	TransactionType    string `xml:"TransactionType"`
+	PostedDate         Time   `xml:"PostedDate"`
+	NetCoTransactionID string `xml:"NetCoTransactionID"`
+	SwapReason         string `xml:"SwapReason"`
+	ASIN               string `xml:"ASIN"`
+	MarketplaceId      string `xml:"MarketplaceId"`
+	TaxExclusiveAmount Money  `xml:"TaxExclusiveAmount"`
+	TaxAmount          Money  `xml:"TaxAmount"`
+}
+
+type RemovalShipmentItem struct {
+	RemovalShipmentItemId string `xml:"RemovalShipmentItemId"`
+	TaxCollectionModel    string `xml:"TaxCollectionModel"`
+	FulfillmentNetworkSKU string `xml:"FulfillmentNetworkSKU"`
+	Quantity              int    `xml:"Quantity"`
+	Revenue               Money  `xml:"Revenue"`
+	FeeAmount             Money  `xml:"FeeAmount"`
+	TaxAmount             Money  `xml:"TaxAmount"`
+	TaxWithheld           Money  `xml:"TaxWithheld"`
+}
+
+type RemovalShipmentEvent struct {
+	PostedDate              Time                  `xml:"PostedDate"`
+	OrderId                 string                `xml:"OrderId"`
+	TransactionType         string                `xml:"TransactionType"`
+	RemovalShipmentItemList []RemovalShipmentItem `xml:"RemovalShipmentItemList>RemovalShipmentItem"`
+}
+
+type TrialShipmentEvent struct {
+	AmazonOrderId         string         `xml:"AmazonOrderId"`
+	FinancialEventGroupId string         `xml:"FinancialEventGroupId"`
+	PostedDate            Time           `xml:"PostedDate"`
+	SKU                   string         `xml:"SKU"`
+	FeeList               []FeeComponent `xml:"FeeList>FeeComponent"`
+}
+
+type TaxWithholdingPeriod struct {
+	StartDate Time `xml:"StartDate"`
+	EndDate   Time `xml:"EndDate"`
+}
+
+type TaxWithholdingEvent struct {
+	PostedDate           Time                 `xml:"PostedDate"`
+	BaseAmount           Money                `xml:"BaseAmount"`
+	WithheldAmount       Money                `xml:"WithheldAmount"`
+	TaxWithholdingPeriod TaxWithholdingPeriod `xml:"TaxWithholdingPeriod"`
+}
+
+// FinancialEvents groups the events of a ListFinancialEvents page by type.
+type FinancialEvents struct {
+	ShipmentEventList                      []ShipmentEvent                      `xml:"ShipmentEventList>ShipmentEvent"`
+	RefundEventList                        []RefundEvent                        `xml:"RefundEventList>ShipmentEvent"`
+	GuaranteeClaimEventList                []GuaranteeClaimEvent                `xml:"GuaranteeClaimEventList>ShipmentEvent"`
+	ChargebackEventList                    []ChargebackEvent                    `xml:"ChargebackEventList>ShipmentEvent"`
+	PayWithAmazonEventList                 []PayWithAmazonEvent                 `xml:"PayWithAmazonEventList>PayWithAmazonEvent"`
+	ServiceProviderCreditEventList         []SolutionProviderCreditEvent        `xml:"ServiceProviderCreditEventList>SolutionProviderCreditEvent"`
+	RetrochargeEventList                   []RetrochargeEvent                   `xml:"RetrochargeEventList>RetrochargeEvent"`
+	RentalTransactionEventList             []RentalTransactionEvent             `xml:"RentalTransactionEventList>RentalTransactionEvent"`
+	PerformanceBondRefundEventList         []PerformanceBondRefundEvent         `xml:"PerformanceBondRefundEventList>PerformanceBondRefundEvent"`
+	ProductAdsPaymentEventList             []ProductAdsPaymentEvent             `xml:"ProductAdsPaymentEventList>ProductAdsPaymentEvent"`
+	ServiceFeeEventList                    []ServiceFeeEvent                    `xml:"ServiceFeeEventList>ServiceFeeEvent"`
+	DebtRecoveryEventList                  []DebtRecoveryEvent                  `xml:"DebtRecoveryEventList>DebtRecoveryEvent"`
+	LoanServicingEventList                 []LoanServicingEvent                 `xml:"LoanServicingEventList>LoanServicingEvent"`
+	AdjustmentEventList                    []AdjustmentEvent                    `xml:"AdjustmentEventList>AdjustmentEvent"`
+	CouponPaymentEventList                 []CouponPaymentEvent                 `xml:"CouponPaymentEventList>CouponPaymentEvent"`
+	SAFETReimbursementEventList            []SAFETReimbursementEvent            `xml:"SAFETReimbursementEventList>SAFETReimbursementEvent"`
+	SellerReviewEnrollmentPaymentEventList []SellerReviewEnrollmentPaymentEvent `xml:"SellerReviewEnrollmentPaymentEventList>SellerReviewEnrollmentPaymentEvent"`
+	FBALiquidationEventList                []FBALiquidationEvent                `xml:"FBALiquidationEventList>FBALiquidationEvent"`
+	ImagingServicesFeeEventList            []ImagingServicesFeeEvent            `xml:"ImagingServicesFeeEventList>ImagingServicesFeeEvent"`
+	AffordabilityExpenseEventList          []AffordabilityExpenseEvent          `xml:"AffordabilityExpenseEventList>AffordabilityExpenseEvent"`
+	AffordabilityExpenseReversalEventList  []AffordabilityExpenseReversalEvent  `xml:"AffordabilityExpenseReversalEventList>AffordabilityExpenseEvent"`
+	NetworkComminglingTransactionEventList []NetworkComminglingTransactionEvent `xml:"NetworkComminglingTransactionEventList>NetworkComminglingTransactionEvent"`
+	RemovalShipmentEventList               []RemovalShipmentEvent               `xml:"RemovalShipmentEventList>RemovalShipmentEvent"`
+	TrialShipmentEventList                 []TrialShipmentEvent                 `xml:"TrialShipmentEventList>TrialShipmentEvent"`
+	TaxWithholdingEventList                []TaxWithholdingEvent                `xml:"TaxWithholdingEventList>TaxWithholdingEvent"`
+}
+
+// FinancialEvent is any one of the typed events in FinancialEvents, such as
+// *ShipmentEvent or *ServiceFeeEvent.
+type FinancialEvent interface{}
+
+// All returns every event on the page, grouped by type in the order of the
+// FinancialEvents fields. Events of one type keep the order Amazon listed
+// them in.
+func (f *FinancialEvents) All() []FinancialEvent {
+	var events []FinancialEvent
+
+	for i := range f.ShipmentEventList {
+		events = append(events, &f.ShipmentEventList[i])
+	}
+	for i := range f.RefundEventList {
+		events = append(events, &f.RefundEventList[i])
+	}
+	for i := range f.GuaranteeClaimEventList {
+		events = append(events, &f.GuaranteeClaimEventList[i])
+	}
+	for i := range f.ChargebackEventList {
+		events = append(events, &f.ChargebackEventList[i])
+	}
+	for i := range f.PayWithAmazonEventList {
+		events = append(events, &f.PayWithAmazonEventList[i])
+	}
+	for i := range f.ServiceProviderCreditEventList {
+		events = append(events, &f.ServiceProviderCreditEventList[i])
+	}
+	for i := range f.RetrochargeEventList {
+		events = append(events, &f.RetrochargeEventList[i])
+	}
+	for i := range f.RentalTransactionEventList {
+		events = append(events, &f.RentalTransactionEventList[i])
+	}
+	for i := range f.PerformanceBondRefundEventList {
+		events = append(events, &f.PerformanceBondRefundEventList[i])
+	}
+	for i := range f.ProductAdsPaymentEventList {
+		events = append(events, &f.ProductAdsPaymentEventList[i])
+	}
+	for i := range f.ServiceFeeEventList {
+		events = append(events, &f.ServiceFeeEventList[i])
+	}
+	for i := range f.DebtRecoveryEventList {
+		events = append(events, &f.DebtRecoveryEventList[i])
+	}
+	for i := range f.LoanServicingEventList {
+		events = append(events, &f.LoanServicingEventList[i])
+	}
+	for i := range f.AdjustmentEventList {
+		events = append(events, &f.AdjustmentEventList[i])
+	}
+	for i := range f.CouponPaymentEventList {
+		events = append(events, &f.CouponPaymentEventList[i])
+	}
+	for i := range f.SAFETReimbursementEventList {
+		events = append(events, &f.SAFETReimbursementEventList[i])
+	}
+	for i := range f.SellerReviewEnrollmentPaymentEventList {
+		events = append(events, &f.SellerReviewEnrollmentPaymentEventList[i])
+	}
+	for i := range f.FBALiquidationEventList {
+		events = append(events, &f.FBALiquidationEventList[i])
+	}
+	for i := range f.ImagingServicesFeeEventList {
+		events = append(events, &f.ImagingServicesFeeEventList[i])
+	}
+	for i := range f.AffordabilityExpenseEventList {
+		events = append(events, &f.AffordabilityExpenseEventList[i])
+	}
+	for i := range f.AffordabilityExpenseReversalEventList {
+		events = append(events, &f.AffordabilityExpenseReversalEventList[i])
+	}
+	for i := range f.NetworkComminglingTransactionEventList {
+		events = append(events, &f.NetworkComminglingTransactionEventList[i])
+	}
+	for i := range f.RemovalShipmentEventList {
+		events = append(events, &f.RemovalShipmentEventList[i])
+	}
+	for i := range f.TrialShipmentEventList {
+		events = append(events, &f.TrialShipmentEventList[i])
+	}
+	for i := range f.TaxWithholdingEventList {
+		events = append(events, &f.TaxWithholdingEventList[i])
+	}
+
+	return events
+}
+
+type FinancialEventGroup struct {
+	FinancialEventGroupId    string `xml:"FinancialEventGroupId"`
+	ProcessingStatus         string `xml:"ProcessingStatus"`
+	FundTransferStatus       string `xml:"FundTransferStatus"`
+	OriginalTotal            Money  `xml:"OriginalTotal"`
+	ConvertedTotal           Money  `xml:"ConvertedTotal"`
+	FundTransferDate         Time   `xml:"FundTransferDate"`
+	TraceId                  string `xml:"TraceId"`
+	AccountTail              string `xml:"AccountTail"`
+	BeginningBalance         Money  `xml:"BeginningBalance"`
+	FinancialEventGroupStart Time   `xml:"FinancialEventGroupStart"`
+	FinancialEventGroupEnd   Time   `xml:"FinancialEventGroupEnd"`
+}
+
+type ListFinancialEventGroupsResult struct {
+	NextToken               string                `xml:"NextToken"`
+	FinancialEventGroupList []FinancialEventGroup `xml:"FinancialEventGroupList>FinancialEventGroup"`
+}
+
+type ListFinancialEventsResult struct {
+	NextToken       string          `xml:"NextToken"`
+	FinancialEvents FinancialEvents `xml:"FinancialEvents"`
+}
+
+type listFinancialEventGroupsResponse struct {
+	Result           ListFinancialEventGroupsResult `xml:"ListFinancialEventGroupsResult"`
+	ResponseMetadata ResponseMetadata               `xml:"ResponseMetadata"`
+}
+
+type listFinancialEventGroupsByNextTokenResponse struct {
+	Result           ListFinancialEventGroupsResult `xml:"ListFinancialEventGroupsByNextTokenResult"`
+	ResponseMetadata ResponseMetadata               `xml:"ResponseMetadata"`
+}
+
+type listFinancialEventsResponse struct {
+	Result           ListFinancialEventsResult `xml:"ListFinancialEventsResult"`
+	ResponseMetadata ResponseMetadata          `xml:"ResponseMetadata"`
+}
+
+type listFinancialEventsByNextTokenResponse struct {
+	Result           ListFinancialEventsResult `xml:"ListFinancialEventsByNextTokenResult"`
+	ResponseMetadata ResponseMetadata          `xml:"ResponseMetadata"`
+}
+
+type ListFinancialEventGroupsRequest struct {
+	FinancialEventGroupStartedAfter  time.Time
+	FinancialEventGroupStartedBefore *time.Time
+	MaxResultsPerPage                *int
+}
+
+func (api AmazonMWSAPI) ListFinancialEventGroups(req ListFinancialEventGroupsRequest) (ListFinancialEventGroupsResult, Quota, error) {
+	params := make(map[string]string)
+
+	params["FinancialEventGroupStartedAfter"] = formatTime(req.FinancialEventGroupStartedAfter)
+	if req.FinancialEventGroupStartedBefore != nil {
+		params["FinancialEventGroupStartedBefore"] = formatTime(*req.FinancialEventGroupStartedBefore)
+	}
+	if req.MaxResultsPerPage != nil {
+		params["MaxResultsPerPage"] = strconv.Itoa(*req.MaxResultsPerPage)
+	}
+
+	var resp listFinancialEventGroupsResponse
+	quota, err := api.fetchAndDecode("ListFinancialEventGroups", "/Finances/2015-05-01", params, nil, &resp)
+
+	return resp.Result, quota, err
+}
+
+func (api AmazonMWSAPI) ListFinancialEventGroupsByNextToken(nextToken string) (ListFinancialEventGroupsResult, Quota, error) {
+	params := make(map[string]string)
+	params["NextToken"] = nextToken
+
+	var resp listFinancialEventGroupsByNextTokenResponse
+	quota, err := api.fetchAndDecode("ListFinancialEventGroupsByNextToken", "/Finances/2015-05-01", params, nil, &resp)
+
+	return resp.Result, quota, err
+}
+
+type ListFinancialEventsRequest struct {
+	MaxResultsPerPage     *int
+	AmazonOrderId         *string
+	FinancialEventGroupId *string
+	PostedAfter           *time.Time
+	PostedBefore          *time.Time
+}
+
+func (api AmazonMWSAPI) ListFinancialEvents(req ListFinancialEventsRequest) (ListFinancialEventsResult, Quota, error) {
+	params := make(map[string]string)
+
+	if req.MaxResultsPerPage != nil {
+		params["MaxResultsPerPage"] = strconv.Itoa(*req.MaxResultsPerPage)
+	}
+	if req.AmazonOrderId != nil {
+		params["AmazonOrderId"] = *req.AmazonOrderId
+	}
+	if req.FinancialEventGroupId != nil {
+		params["FinancialEventGroupId"] = *req.FinancialEventGroupId
+	}
+	if req.PostedAfter != nil {
+		params["PostedAfter"] = formatTime(*req.PostedAfter)
+	}
+	if req.PostedBefore != nil {
+		params["PostedBefore"] = formatTime(*req.PostedBefore)
+	}
+
+	var resp listFinancialEventsResponse
+	quota, err := api.fetchAndDecode("ListFinancialEvents", "/Finances/2015-05-01", params, nil, &resp)
+
+	return resp.Result, quota, err
+}
+
+func (api AmazonMWSAPI) ListFinancialEventsByNextToken(nextToken string) (ListFinancialEventsResult, Quota, error) {
+	params := make(map[string]string)
+	params["NextToken"] = nextToken
+
+	var resp listFinancialEventsByNextTokenResponse
+	quota, err := api.fetchAndDecode("ListFinancialEventsByNextToken", "/Finances/2015-05-01", params, nil, &resp)
+
+	return resp.Result, quota, err
+}
+
+// EachFinancialEvent calls fn for every financial event posted between
+// postedAfter and postedBefore, following NextToken until the last page and
+// pacing the requests within the ListFinancialEvents quota. A zero
+// postedBefore leaves the range open-ended. Iteration stops at the first
+// error returned by fn.
+func (api AmazonMWSAPI) EachFinancialEvent(ctx context.Context, postedAfter, postedBefore time.Time, fn func(FinancialEvent) error) error {
+	pacer := api.pacer("ListFinancialEvents")
+
+	req := ListFinancialEventsRequest{PostedAfter: &postedAfter}
+	if !postedBefore.IsZero() {
+		req.PostedBefore = &postedBefore
+	}
+
+	nextToken := ""
+	for {
+		var result ListFinancialEventsResult
+		_, _, err := fetchWithRetry(ctx, api.telemetry(), "ListFinancialEvents", pacer, 1, func(ctx context.Context) (string, Quota, error) {
+			var quota Quota
+			var err error
+			if nextToken == "" {
+				result, quota, err = api.WithContext(ctx).ListFinancialEvents(req)
+			} else {
+				result, quota, err = api.WithContext(ctx).ListFinancialEventsByNextToken(nextToken)
+			}
+			return "", quota, err
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, event := range result.FinancialEvents.All() {
+			if err := fn(event); err != nil {
+				return err
+			}
+		}
+
+		if result.NextToken == "" {
+			return nil
+		}
+		nextToken = result.NextToken
+	}
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,175 @@
+package amazonmws
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+const listFinancialEventsBody = `<?xml version="1.0"?>
+<ListFinancialEventsResponse xmlns="http://mws.amazonservices.com/Finances/2015-05-01">
+  <ListFinancialEventsResult>
+    <NextToken>2YgYW55IGNhcm5hbCBwbGVhc3VyZS4=</NextToken>
+    <FinancialEvents>
+      <ShipmentEventList>
+        <ShipmentEvent>
+          <AmazonOrderId>333-7777777-7777777</AmazonOrderId>
+          <MarketplaceName>amazon.com</MarketplaceName>
+          <PostedDate>2012-07-18T00:00:00.000Z</PostedDate>
+          <ShipmentItemList>
+            <ShipmentItem>
+              <SellerSKU>CBA_OTF_1</SellerSKU>
+              <QuantityShipped>2</QuantityShipped>
+              <ItemChargeList>
+                <ChargeComponent>
+                  <ChargeType>Principal</ChargeType>
+                  <ChargeAmount><CurrencyAmount>10.00</CurrencyAmount><CurrencyCode>USD</CurrencyCode></ChargeAmount>
+                </ChargeComponent>
+              </ItemChargeList>
+            </ShipmentItem>
+          </ShipmentItemList>
+        </ShipmentEvent>
+      </ShipmentEventList>
+      <RefundEventList>
+        <ShipmentEvent>
+          <AmazonOrderId>333-8888888-8888888</AmazonOrderId>
+          <PostedDate/>
+        </ShipmentEvent>
+      </RefundEventList>
+      <ServiceFeeEventList>
+        <ServiceFeeEvent>
+          <FeeReason>fba inbound defect fee</FeeReason>
+          <FeeList>
+            <FeeComponent>
+              <FeeType>FBAInboundDefectFee</FeeType>
+              <FeeAmount><CurrencyAmount>-1.5</CurrencyAmount><CurrencyCode>USD</CurrencyCode></FeeAmount>
+            </FeeComponent>
+          </FeeList>
+        </ServiceFeeEvent>
+      </ServiceFeeEventList>
+    </FinancialEvents>
+  </ListFinancialEventsResult>
+  <ResponseMetadata><RequestId>1105b931-6f1c-4480-8e97-f3b467840a9e</RequestId></ResponseMetadata>
+</ListFinancialEventsResponse>`
+
+func TestDecodeListFinancialEvents(t *testing.T) {
+	var resp listFinancialEventsResponse
+	err := decodeResponse(listFinancialEventsBody, &resp)
+	assert.Nil(t, err)
+
+	result := resp.Result
+	assert.Equal(t, "2YgYW55IGNhcm5hbCBwbGVhc3VyZS4=", result.NextToken)
+	assert.Equal(t, "1105b931-6f1c-4480-8e97-f3b467840a9e", resp.ResponseMetadata.RequestId)
+
+	shipment := result.FinancialEvents.ShipmentEventList[0]
+	assert.Equal(t, "333-7777777-7777777", shipment.AmazonOrderId)
+	assert.Equal(t, time.Date(2012, 7, 18, 0, 0, 0, 0, time.UTC), shipment.PostedDate.Time)
+	assert.Equal(t, 2, shipment.ShipmentItemList[0].QuantityShipped)
+	assert.Equal(t, Money{CurrencyCode: "USD", CurrencyAmount: "10.00"}, shipment.ShipmentItemList[0].ItemChargeList[0].ChargeAmount)
+
+	events := result.FinancialEvents.All()
+	assert.Len(t, events, 3)
+	assert.Equal(t, "333-8888888-8888888", events[1].(*RefundEvent).AmazonOrderId)
+	assert.True(t, events[1].(*RefundEvent).PostedDate.IsZero())
+	assert.Equal(t, "FBAInboundDefectFee", events[2].(*ServiceFeeEvent).FeeList[0].FeeType)
+}
+
+func TestDecodeErrorResponse(t *testing.T) {
+	body := `<ErrorResponse xmlns="http://mws.amazonservices.com/Finances/2015-05-01"><Error><Type>Sender</Type><Code>InvalidParameterValue</Code><Message>PostedAfter is required</Message></Error><RequestID>abc</RequestID></ErrorResponse>`
+
+	var resp listFinancialEventsResponse
+	err := decodeResponse(body, &resp)
+
+	assert.Equal(t, &MWSError{Type: "Sender", Code: "InvalidParameterValue", Message: "PostedAfter is required", RequestId: "abc"}, err)
+}
+
+func TestMoneyAdd(t *testing.T) {
+	sum, err := Money{"USD", "10.00"}.Add(Money{"USD", "-1.5"})
+	assert.Nil(t, err)
+	assert.Equal(t, Money{"USD", "8.50"}, sum)
+
+	cents, err := sum.MinorUnits()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(850), cents)
+
+	_, err = sum.Add(Money{"EUR", "1.00"})
+	assert.NotNil(t, err)
+}
+
+func TestMoneyMinorUnits(t *testing.T) {
+	yen, err := Money{"JPY", "1200"}.MinorUnits()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1200), yen)
+
+	fils, err := Money{"KWD", "1.250"}.MinorUnits()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1250), fils)
+
+	_, err = Money{"JPY", "1200.5"}.MinorUnits()
+	assert.NotNil(t, err)
+}
+
+func TestEachFinancialEventFollowsNextToken(t *testing.T) {
+	pages := map[string]string{
+		"ListFinancialEvents": `<ListFinancialEventsResponse><ListFinancialEventsResult>
+  <NextToken>page-2</NextToken>
+  <FinancialEvents>
+    <ShipmentEventList><ShipmentEvent><AmazonOrderId>111-1</AmazonOrderId></ShipmentEvent><ShipmentEvent><AmazonOrderId>111-2</AmazonOrderId></ShipmentEvent></ShipmentEventList>
+  </FinancialEvents>
+</ListFinancialEventsResult></ListFinancialEventsResponse>`,
+		"ListFinancialEventsByNextToken": `<ListFinancialEventsByNextTokenResponse><ListFinancialEventsByNextTokenResult>
+  <FinancialEvents>
+    <RefundEventList><ShipmentEvent><AmazonOrderId>111-3</AmazonOrderId></ShipmentEvent></RefundEventList>
+  </FinancialEvents>
+</ListFinancialEventsByNextTokenResult></ListFinancialEventsByNextTokenResponse>`,
+	}
+
+	var seen []*Request
+	amazon := func(next RoundTripper) RoundTripper {
+		return RoundTripperFunc(func(req *Request) (*Response, error) {
+			seen = append(seen, req)
+			return &Response{StatusCode: 200, Body: pages[req.Action]}, nil
+		})
+	}
+	api := AmazonMWSAPI{Host: offlineHost, SellerId: "A1SELLER", Middleware: []Middleware{amazon}}
+	postedAfter := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var orders []string
+	err := api.EachFinancialEvent(context.Background(), postedAfter, time.Time{}, func(event FinancialEvent) error {
+		switch e := event.(type) {
+		case *ShipmentEvent:
+			orders = append(orders, "shipment "+e.AmazonOrderId)
+		case *RefundEvent:
+			orders = append(orders, "refund "+e.AmazonOrderId)
+		}
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"shipment 111-1", "shipment 111-2", "refund 111-3"}, orders)
+	assert.Len(t, seen, 2)
+	assert.Equal(t, "2021-01-01T00:00:00Z", seen[0].Params["PostedAfter"])
+	assert.NotContains(t, seen[0].Params, "PostedBefore")
+	assert.Equal(t, "ListFinancialEventsByNextToken", seen[1].Action)
+	assert.Equal(t, "page-2", seen[1].Params["NextToken"])
+
+	seen = nil
+	stop := errors.New("stop")
+	calls := 0
+	err = api.EachFinancialEvent(context.Background(), postedAfter, time.Time{}, func(event FinancialEvent) error {
+		calls++
+		return stop
+	})
+
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 1, calls)
+	assert.Len(t, seen, 1)
+}
+
+func TestFinancesByNextTokenSharesQuota(t *testing.T) {
+	assert.Equal(t, limiterFor("finances-test", "ListFinancialEvents", ""), limiterFor("finances-test", "ListFinancialEventsByNextToken", ""))
+	assert.Equal(t, limiterFor("finances-test", "ListFinancialEventGroups", ""), limiterFor("finances-test", "ListFinancialEventGroupsByNextToken", ""))
+	assert.NotEqual(t, limiterFor("finances-test", "ListFinancialEvents", ""), limiterFor("finances-test", "ListFinancialEventGroups", ""))
+}
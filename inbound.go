@@ -0,0 +1,650 @@
+package amazonmws
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"errors"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Address is a ship-from or ship-to address for an inbound shipment.
+type Address struct {
+	Name                string `xml:"Name"`
+	AddressLine1        string `xml:"AddressLine1"`
+	AddressLine2        string `xml:"AddressLine2"`
+	City                string `xml:"City"`
+	DistrictOrCounty    string `xml:"DistrictOrCounty"`
+	StateOrProvinceCode string `xml:"StateOrProvinceCode"`
+	CountryCode         string `xml:"CountryCode"`
+	PostalCode          string `xml:"PostalCode"`
+}
+
+func (a Address) appendQuery(params map[string]string, prefix string) {
+	params[prefix+".Name"] = a.Name
+	params[prefix+".AddressLine1"] = a.AddressLine1
+	if a.AddressLine2 != "" {
+		params[prefix+".AddressLine2"] = a.AddressLine2
+	}
+	params[prefix+".City"] = a.City
+	if a.DistrictOrCounty != "" {
+		params[prefix+".DistrictOrCounty"] = a.DistrictOrCounty
+	}
+	if a.StateOrProvinceCode != "" {
+		params[prefix+".StateOrProvinceCode"] = a.StateOrProvinceCode
+	}
+	params[prefix+".CountryCode"] = a.CountryCode
+	if a.PostalCode != "" {
+		params[prefix+".PostalCode"] = a.PostalCode
+	}
+}
+
+// PrepDetails names a prep instruction, such as Polybagging, and who performs
+// it (AMAZON or SELLER).
+type PrepDetails struct {
+	PrepInstruction string `xml:"PrepInstruction"`
+	PrepOwner       string `xml:"PrepOwner"`
+}
+
+func appendPrepDetails(params map[string]string, prefix string, list []PrepDetails) {
+	for i, p := range list {
+		key := prefix + ".PrepDetailsList.PrepDetails." + strconv.Itoa(i+1)
+		params[key+".PrepInstruction"] = p.PrepInstruction
+		params[key+".PrepOwner"] = p.PrepOwner
+	}
+}
+
+type InboundShipmentPlanRequestItem struct {
+	SellerSKU       string
+	ASIN            string
+	Condition       string
+	Quantity        int
+	QuantityInCase  *int
+	PrepDetailsList []PrepDetails
+}
+
+func (item InboundShipmentPlanRequestItem) appendQuery(params map[string]string, index int) {
+	prefix := "InboundShipmentPlanRequestItems.member." + strconv.Itoa(index+1)
+
+	params[prefix+".SellerSKU"] = item.SellerSKU
+	if item.ASIN != "" {
+		params[prefix+".ASIN"] = item.ASIN
+	}
+	if item.Condition != "" {
+		params[prefix+".Condition"] = item.Condition
+	}
+	params[prefix+".Quantity"] = strconv.Itoa(item.Quantity)
+	if item.QuantityInCase != nil {
+		params[prefix+".QuantityInCase"] = strconv.Itoa(*item.QuantityInCase)
+	}
+	appendPrepDetails(params, prefix, item.PrepDetailsList)
+}
+
+type CreateInboundShipmentPlanRequest struct {
+	ShipFromAddress              Address
+	ShipToCountryCode            string
+	ShipToCountrySubdivisionCode string
+	LabelPrepPreference          string
+	Items                        []InboundShipmentPlanRequestItem
+}
+
+type InboundShipmentPlanItem struct {
+	SellerSKU             string        `xml:"SellerSKU"`
+	FulfillmentNetworkSKU string        `xml:"FulfillmentNetworkSKU"`
+	Quantity              int           `xml:"Quantity"`
+	PrepDetailsList       []PrepDetails `xml:"PrepDetailsList>PrepDetails"`
+}
+
+// Amount is a currency value in the Fulfillment Inbound and Outbound APIs.
+type Amount struct {
+	CurrencyCode string `xml:"CurrencyCode"`
+	Value        string `xml:"Value"`
+}
+
+type BoxContentsFeeDetails struct {
+	TotalUnits int    `xml:"TotalUnits"`
+	FeePerUnit Amount `xml:"FeePerUnit"`
+	TotalFee   Amount `xml:"TotalFee"`
+}
+
+type InboundShipmentPlan struct {
+	ShipmentId                     string                    `xml:"ShipmentId"`
+	DestinationFulfillmentCenterId string                    `xml:"DestinationFulfillmentCenterId"`
+	ShipToAddress                  Address                   `xml:"ShipToAddress"`
+	LabelPrepType                  string                    `xml:"LabelPrepType"`
+	Items                          []InboundShipmentPlanItem `xml:"Items>member"`
+	EstimatedBoxContentsFee        *BoxContentsFeeDetails    `xml:"EstimatedBoxContentsFee"`
+}
+
+type createInboundShipmentPlanResponse struct {
+	Plans            []InboundShipmentPlan `xml:"CreateInboundShipmentPlanResult>InboundShipmentPlans>member"`
+	ResponseMetadata ResponseMetadata      `xml:"ResponseMetadata"`
+}
+
+func (api AmazonMWSAPI) CreateInboundShipmentPlan(req CreateInboundShipmentPlanRequest) ([]InboundShipmentPlan, Quota, error) {
+	params := make(map[string]string)
+
+	req.ShipFromAddress.appendQuery(params, "ShipFromAddress")
+	if req.ShipToCountryCode != "" {
+		params["ShipToCountryCode"] = req.ShipToCountryCode
+	}
+	if req.ShipToCountrySubdivisionCode != "" {
+		params["ShipToCountrySubdivisionCode"] = req.ShipToCountrySubdivisionCode
+	}
+	if req.LabelPrepPreference != "" {
+		params["LabelPrepPreference"] = req.LabelPrepPreference
+	}
+	for i, item := range req.Items {
+		item.appendQuery(params, i)
+	}
+
+	var resp createInboundShipmentPlanResponse
+	quota, err := api.fetchAndDecode("CreateInboundShipmentPlan", "/FulfillmentInboundShipment/2010-10-01", params, nil, &resp)
+
+	return resp.Plans, quota, err
+}
+
+type InboundShipmentHeader struct {
+	ShipmentName                   string
+	ShipFromAddress                Address
+	DestinationFulfillmentCenterId string
+	LabelPrepPreference            string
+	AreCasesRequired               *bool
+	ShipmentStatus                 string
+	IntendedBoxContentsSource      string
+}
+
+func (h InboundShipmentHeader) appendQuery(params map[string]string) {
+	prefix := "InboundShipmentHeader"
+
+	params[prefix+".ShipmentName"] = h.ShipmentName
+	h.ShipFromAddress.appendQuery(params, prefix+".ShipFromAddress")
+	params[prefix+".DestinationFulfillmentCenterId"] = h.DestinationFulfillmentCenterId
+	if h.LabelPrepPreference != "" {
+		params[prefix+".LabelPrepPreference"] = h.LabelPrepPreference
+	}
+	if h.AreCasesRequired != nil {
+		params[prefix+".AreCasesRequired"] = strconv.FormatBool(*h.AreCasesRequired)
+	}
+	if h.ShipmentStatus != "" {
+		params[prefix+".ShipmentStatus"] = h.ShipmentStatus
+	}
+	if h.IntendedBoxContentsSource != "" {
+		params[prefix+".IntendedBoxContentsSource"] = h.IntendedBoxContentsSource
+	}
+}
+
+type InboundShipmentItem struct {
+	ShipmentId            string        `xml:"ShipmentId"`
+	SellerSKU             string        `xml:"SellerSKU"`
+	FulfillmentNetworkSKU string        `xml:"FulfillmentNetworkSKU"`
+	QuantityShipped       int           `xml:"QuantityShipped"`
+	QuantityReceived      int           `xml:"QuantityReceived"`
+	QuantityInCase        int           `xml:"QuantityInCase"`
+	PrepDetailsList       []PrepDetails `xml:"PrepDetailsList>PrepDetails"`
+	ReleaseDate           string        `xml:"ReleaseDate"`
+}
+
+func (item InboundShipmentItem) appendQuery(params map[string]string, index int) {
+	prefix := "InboundShipmentItems.member." + strconv.Itoa(index+1)
+
+	params[prefix+".SellerSKU"] = item.SellerSKU
+	params[prefix+".QuantityShipped"] = strconv.Itoa(item.QuantityShipped)
+	if item.QuantityInCase > 0 {
+		params[prefix+".QuantityInCase"] = strconv.Itoa(item.QuantityInCase)
+	}
+	if item.ReleaseDate != "" {
+		params[prefix+".ReleaseDate"] = item.ReleaseDate
+	}
+	appendPrepDetails(params, prefix, item.PrepDetailsList)
+}
+
+// InboundShipmentRequest is the body of both CreateInboundShipment and
+// UpdateInboundShipment.
+type InboundShipmentRequest struct {
+	ShipmentId string
+	Header     InboundShipmentHeader
+	Items      []InboundShipmentItem
+}
+
+func (req InboundShipmentRequest) toQuery() map[string]string {
+	params := make(map[string]string)
+
+	params["ShipmentId"] = req.ShipmentId
+	req.Header.appendQuery(params)
+	for i, item := range req.Items {
+		item.appendQuery(params, i)
+	}
+
+	return params
+}
+
+type createInboundShipmentResponse struct {
+	ShipmentId       string           `xml:"CreateInboundShipmentResult>ShipmentId"`
+	ResponseMetadata ResponseMetadata `xml:"ResponseMetadata"`
+}
+
+type updateInboundShipmentResponse struct {
+	ShipmentId       string           `xml:"UpdateInboundShipmentResult>ShipmentId"`
+	ResponseMetadata ResponseMetadata `xml:"ResponseMetadata"`
+}
+
+// CreateInboundShipment creates a shipment from a plan returned by
+// CreateInboundShipmentPlan and returns its ShipmentId.
+func (api AmazonMWSAPI) CreateInboundShipment(req InboundShipmentRequest) (string, Quota, error) {
+	var resp createInboundShipmentResponse
+	quota, err := api.fetchAndDecode("CreateInboundShipment", "/FulfillmentInboundShipment/2010-10-01", req.toQuery(), nil, &resp)
+
+	return resp.ShipmentId, quota, err
+}
+
+// UpdateInboundShipment updates the header and items of an existing shipment
+// and returns its ShipmentId.
+func (api AmazonMWSAPI) UpdateInboundShipment(req InboundShipmentRequest) (string, Quota, error) {
+	var resp updateInboundShipmentResponse
+	quota, err := api.fetchAndDecode("UpdateInboundShipment", "/FulfillmentInboundShipment/2010-10-01", req.toQuery(), nil, &resp)
+
+	return resp.ShipmentId, quota, err
+}
+
+type Dimensions struct {
+	Unit   string `xml:"Unit"`
+	Length string `xml:"Length"`
+	Width  string `xml:"Width"`
+	Height string `xml:"Height"`
+}
+
+func (d Dimensions) appendQuery(params map[string]string, prefix string) {
+	params[prefix+".Unit"] = d.Unit
+	params[prefix+".Length"] = d.Length
+	params[prefix+".Width"] = d.Width
+	params[prefix+".Height"] = d.Height
+}
+
+type Weight struct {
+	Unit  string `xml:"Unit"`
+	Value string `xml:"Value"`
+}
+
+func (w Weight) appendQuery(params map[string]string, prefix string) {
+	params[prefix+".Unit"] = w.Unit
+	params[prefix+".Value"] = w.Value
+}
+
+// PartneredSmallParcelPackage is a box shipped with an Amazon-partnered
+// small parcel carrier.
+type PartneredSmallParcelPackage struct {
+	Dimensions Dimensions
+	Weight     Weight
+}
+
+type Contact struct {
+	Name  string
+	Phone string
+	Email string
+	Fax   string
+}
+
+type Pallet struct {
+	Dimensions Dimensions
+	Weight     *Weight
+	IsStacked  bool
+}
+
+type PartneredLtlData struct {
+	Contact             Contact
+	BoxCount            int
+	SellerFreightClass  string
+	FreightReadyDate    string
+	PalletList          []Pallet
+	TotalWeight         *Weight
+	SellerDeclaredValue *Amount
+}
+
+// TransportDetails holds exactly one of the four transport variants, matching
+// the IsPartnered and ShipmentType of the PutTransportContent request.
+type TransportDetails struct {
+	PartneredSmallParcelPackages []PartneredSmallParcelPackage
+
+	NonPartneredSmallParcelCarrier     string
+	NonPartneredSmallParcelTrackingIds []string
+
+	PartneredLtl *PartneredLtlData
+
+	NonPartneredLtlCarrier   string
+	NonPartneredLtlProNumber string
+}
+
+func (t TransportDetails) appendQuery(params map[string]string) {
+	for i, p := range t.PartneredSmallParcelPackages {
+		prefix := "TransportDetails.PartneredSmallParcelData.PackageList.member." + strconv.Itoa(i+1)
+		p.Dimensions.appendQuery(params, prefix+".Dimensions")
+		p.Weight.appendQuery(params, prefix+".Weight")
+	}
+
+	if t.NonPartneredSmallParcelCarrier != "" {
+		params["TransportDetails.NonPartneredSmallParcelData.CarrierName"] = t.NonPartneredSmallParcelCarrier
+		for i, id := range t.NonPartneredSmallParcelTrackingIds {
+			params["TransportDetails.NonPartneredSmallParcelData.PackageList.member."+strconv.Itoa(i+1)+".TrackingId"] = id
+		}
+	}
+
+	if ltl := t.PartneredLtl; ltl != nil {
+		prefix := "TransportDetails.PartneredLtlData"
+		params[prefix+".Contact.Name"] = ltl.Contact.Name
+		params[prefix+".Contact.Phone"] = ltl.Contact.Phone
+		params[prefix+".Contact.Email"] = ltl.Contact.Email
+		params[prefix+".Contact.Fax"] = ltl.Contact.Fax
+		params[prefix+".BoxCount"] = strconv.Itoa(ltl.BoxCount)
+		if ltl.SellerFreightClass != "" {
+			params[prefix+".SellerFreightClass"] = ltl.SellerFreightClass
+		}
+		params[prefix+".FreightReadyDate"] = ltl.FreightReadyDate
+		for i, pallet := range ltl.PalletList {
+			key := prefix + ".PalletList.member." + strconv.Itoa(i+1)
+			pallet.Dimensions.appendQuery(params, key+".Dimensions")
+			if pallet.Weight != nil {
+				pallet.Weight.appendQuery(params, key+".Weight")
+			}
+			params[key+".IsStacked"] = strconv.FormatBool(pallet.IsStacked)
+		}
+		if ltl.TotalWeight != nil {
+			ltl.TotalWeight.appendQuery(params, prefix+".TotalWeight")
+		}
+		if ltl.SellerDeclaredValue != nil {
+			params[prefix+".SellerDeclaredValue.CurrencyCode"] = ltl.SellerDeclaredValue.CurrencyCode
+			params[prefix+".SellerDeclaredValue.Value"] = ltl.SellerDeclaredValue.Value
+		}
+	}
+
+	if t.NonPartneredLtlCarrier != "" {
+		params["TransportDetails.NonPartneredLtlData.CarrierName"] = t.NonPartneredLtlCarrier
+		params["TransportDetails.NonPartneredLtlData.ProNumber"] = t.NonPartneredLtlProNumber
+	}
+}
+
+type PutTransportContentRequest struct {
+	ShipmentId       string
+	IsPartnered      bool
+	ShipmentType     string
+	TransportDetails TransportDetails
+}
+
+// TransportResult reports the TransportStatus of a shipment, such as WORKING,
+// ESTIMATED or CONFIRMED.
+type TransportResult struct {
+	TransportStatus string `xml:"TransportStatus"`
+}
+
+type putTransportContentResponse struct {
+	TransportResult  TransportResult  `xml:"PutTransportContentResult>TransportResult"`
+	ResponseMetadata ResponseMetadata `xml:"ResponseMetadata"`
+}
+
+type estimateTransportRequestResponse struct {
+	TransportResult  TransportResult  `xml:"EstimateTransportRequestResult>TransportResult"`
+	ResponseMetadata ResponseMetadata `xml:"ResponseMetadata"`
+}
+
+type confirmTransportRequestResponse struct {
+	TransportResult  TransportResult  `xml:"ConfirmTransportRequestResult>TransportResult"`
+	ResponseMetadata ResponseMetadata `xml:"ResponseMetadata"`
+}
+
+func (api AmazonMWSAPI) PutTransportContent(req PutTransportContentRequest) (TransportResult, Quota, error) {
+	params := make(map[string]string)
+
+	params["ShipmentId"] = req.ShipmentId
+	params["IsPartnered"] = strconv.FormatBool(req.IsPartnered)
+	params["ShipmentType"] = req.ShipmentType
+	req.TransportDetails.appendQuery(params)
+
+	var resp putTransportContentResponse
+	quota, err := api.fetchAndDecode("PutTransportContent", "/FulfillmentInboundShipment/2010-10-01", params, nil, &resp)
+
+	return resp.TransportResult, quota, err
+}
+
+func (api AmazonMWSAPI) EstimateTransportRequest(shipmentId string) (TransportResult, Quota, error) {
+	params := make(map[string]string)
+	params["ShipmentId"] = shipmentId
+
+	var resp estimateTransportRequestResponse
+	quota, err := api.fetchAndDecode("EstimateTransportRequest", "/FulfillmentInboundShipment/2010-10-01", params, nil, &resp)
+
+	return resp.TransportResult, quota, err
+}
+
+func (api AmazonMWSAPI) ConfirmTransportRequest(shipmentId string) (TransportResult, Quota, error) {
+	params := make(map[string]string)
+	params["ShipmentId"] = shipmentId
+
+	var resp confirmTransportRequestResponse
+	quota, err := api.fetchAndDecode("ConfirmTransportRequest", "/FulfillmentInboundShipment/2010-10-01", params, nil, &resp)
+
+	return resp.TransportResult, quota, err
+}
+
+// TransportDocument is a set of package labels. Amazon sends the labels as a
+// base64-encoded zip archive holding a PDF, together with its MD5 checksum.
+type TransportDocument struct {
+	PdfDocument string `xml:"PdfDocument"`
+	Checksum    string `xml:"Checksum"`
+}
+
+// ErrChecksumMismatch is returned when a decoded document does not match the
+// checksum Amazon sent with it.
+var ErrChecksumMismatch = errors.New("amazonmws: document checksum mismatch")
+
+// Decode returns the decoded document bytes after verifying the checksum.
+func (d TransportDocument) Decode() ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(d.PdfDocument)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.Checksum != "" {
+		sum := md5.Sum(data)
+		if base64.StdEncoding.EncodeToString(sum[:]) != d.Checksum {
+			return nil, ErrChecksumMismatch
+		}
+	}
+
+	return data, nil
+}
+
+// PDF returns the label PDF, extracting it from the zip archive Amazon wraps
+// it in. Documents that are not zipped are returned as decoded.
+func (d TransportDocument) PDF() ([]byte, error) {
+	data, err := d.Decode()
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return data, nil
+	}
+
+	for _, f := range archive.File {
+		if !strings.HasSuffix(strings.ToLower(f.Name), ".pdf") {
+			continue
+		}
+
+		r, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+
+		return ioutil.ReadAll(r)
+	}
+
+	return nil, errors.New("amazonmws: transport document contains no PDF")
+}
+
+type getPackageLabelsResponse struct {
+	TransportDocument TransportDocument `xml:"GetPackageLabelsResult>TransportDocument"`
+	ResponseMetadata  ResponseMetadata  `xml:"ResponseMetadata"`
+}
+
+type getUniquePackageLabelsResponse struct {
+	TransportDocument TransportDocument `xml:"GetUniquePackageLabelsResult>TransportDocument"`
+	ResponseMetadata  ResponseMetadata  `xml:"ResponseMetadata"`
+}
+
+// GetPackageLabels returns labels for numberOfPackages packages of a shipment
+// printed on pageType, such as PackageLabel_Letter_2.
+func (api AmazonMWSAPI) GetPackageLabels(shipmentId, pageType string, numberOfPackages int) (TransportDocument, Quota, error) {
+	params := make(map[string]string)
+
+	params["ShipmentId"] = shipmentId
+	params["PageType"] = pageType
+	if numberOfPackages > 0 {
+		params["NumberOfPackages"] = strconv.Itoa(numberOfPackages)
+	}
+
+	var resp getPackageLabelsResponse
+	quota, err := api.fetchAndDecode("GetPackageLabels", "/FulfillmentInboundShipment/2010-10-01", params, nil, &resp)
+
+	return resp.TransportDocument, quota, err
+}
+
+// GetUniquePackageLabels returns labels for the given carton IDs of a shipment.
+func (api AmazonMWSAPI) GetUniquePackageLabels(shipmentId, pageType string, packageLabelsToPrint []string) (TransportDocument, Quota, error) {
+	params := make(map[string]string)
+
+	params["ShipmentId"] = shipmentId
+	params["PageType"] = pageType
+	for i, v := range packageLabelsToPrint {
+		params["PackageLabelsToPrint.member."+strconv.Itoa(i+1)] = v
+	}
+
+	var resp getUniquePackageLabelsResponse
+	quota, err := api.fetchAndDecode("GetUniquePackageLabels", "/FulfillmentInboundShipment/2010-10-01", params, nil, &resp)
+
+	return resp.TransportDocument, quota, err
+}
+
+type InboundShipmentInfo struct {
+	ShipmentId                     string                 `xml:"ShipmentId"`
+	ShipmentName                   string                 `xml:"ShipmentName"`
+	ShipFromAddress                Address                `xml:"ShipFromAddress"`
+	DestinationFulfillmentCenterId string                 `xml:"DestinationFulfillmentCenterId"`
+	ShipmentStatus                 string                 `xml:"ShipmentStatus"`
+	LabelPrepType                  string                 `xml:"LabelPrepType"`
+	AreCasesRequired               bool                   `xml:"AreCasesRequired"`
+	ConfirmedNeedByDate            string                 `xml:"ConfirmedNeedByDate"`
+	BoxContentsSource              string                 `xml:"BoxContentsSource"`
+	EstimatedBoxContentsFee        *BoxContentsFeeDetails `xml:"EstimatedBoxContentsFee"`
+}
+
+type ListInboundShipmentsResult struct {
+	NextToken    string                `xml:"NextToken"`
+	ShipmentData []InboundShipmentInfo `xml:"ShipmentData>member"`
+}
+
+type listInboundShipmentsResponse struct {
+	Result           ListInboundShipmentsResult `xml:"ListInboundShipmentsResult"`
+	ResponseMetadata ResponseMetadata           `xml:"ResponseMetadata"`
+}
+
+type listInboundShipmentsByNextTokenResponse struct {
+	Result           ListInboundShipmentsResult `xml:"ListInboundShipmentsByNextTokenResult"`
+	ResponseMetadata ResponseMetadata           `xml:"ResponseMetadata"`
+}
+
+type ListInboundShipmentsRequest struct {
+	ShipmentStatusList []string
+	ShipmentIdList     []string
+	LastUpdatedAfter   *time.Time
+	LastUpdatedBefore  *time.Time
+}
+
+func (api AmazonMWSAPI) ListInboundShipments(req ListInboundShipmentsRequest) (ListInboundShipmentsResult, Quota, error) {
+	params := make(map[string]string)
+
+	for i, v := range req.ShipmentStatusList {
+		params["ShipmentStatusList.member."+strconv.Itoa(i+1)] = v
+	}
+	for i, v := range req.ShipmentIdList {
+		params["ShipmentIdList.member."+strconv.Itoa(i+1)] = v
+	}
+	if req.LastUpdatedAfter != nil {
+		params["LastUpdatedAfter"] = formatTime(*req.LastUpdatedAfter)
+	}
+	if req.LastUpdatedBefore != nil {
+		params["LastUpdatedBefore"] = formatTime(*req.LastUpdatedBefore)
+	}
+
+	var resp listInboundShipmentsResponse
+	quota, err := api.fetchAndDecode("ListInboundShipments", "/FulfillmentInboundShipment/2010-10-01", params, nil, &resp)
+
+	return resp.Result, quota, err
+}
+
+func (api AmazonMWSAPI) ListInboundShipmentsByNextToken(nextToken string) (ListInboundShipmentsResult, Quota, error) {
+	params := make(map[string]string)
+	params["NextToken"] = nextToken
+
+	var resp listInboundShipmentsByNextTokenResponse
+	quota, err := api.fetchAndDecode("ListInboundShipmentsByNextToken", "/FulfillmentInboundShipment/2010-10-01", params, nil, &resp)
+
+	return resp.Result, quota, err
+}
+
+type ListInboundShipmentItemsResult struct {
+	NextToken string                `xml:"NextToken"`
+	ItemData  []InboundShipmentItem `xml:"ItemData>member"`
+}
+
+type listInboundShipmentItemsResponse struct {
+	Result           ListInboundShipmentItemsResult `xml:"ListInboundShipmentItemsResult"`
+	ResponseMetadata ResponseMetadata               `xml:"ResponseMetadata"`
+}
+
+type listInboundShipmentItemsByNextTokenResponse struct {
+	Result           ListInboundShipmentItemsResult `xml:"ListInboundShipmentItemsByNextTokenResult"`
+	ResponseMetadata ResponseMetadata               `xml:"ResponseMetadata"`
+}
+
+// ListInboundShipmentItemsRequest selects items either by ShipmentId or by
+// the LastUpdatedAfter and LastUpdatedBefore range.
+type ListInboundShipmentItemsRequest struct {
+	ShipmentId        string
+	LastUpdatedAfter  *time.Time
+	LastUpdatedBefore *time.Time
+}
+
+func (api AmazonMWSAPI) ListInboundShipmentItems(req ListInboundShipmentItemsRequest) (ListInboundShipmentItemsResult, Quota, error) {
+	params := make(map[string]string)
+
+	if req.ShipmentId != "" {
+		params["ShipmentId"] = req.ShipmentId
+	}
+	if req.LastUpdatedAfter != nil {
+		params["LastUpdatedAfter"] = formatTime(*req.LastUpdatedAfter)
+	}
+	if req.LastUpdatedBefore != nil {
+		params["LastUpdatedBefore"] = formatTime(*req.LastUpdatedBefore)
+	}
+
+	var resp listInboundShipmentItemsResponse
+	quota, err := api.fetchAndDecode("ListInboundShipmentItems", "/FulfillmentInboundShipment/2010-10-01", params, nil, &resp)
+
+	return resp.Result, quota, err
+}
+
+func (api AmazonMWSAPI) ListInboundShipmentItemsByNextToken(nextToken string) (ListInboundShipmentItemsResult, Quota, error) {
+	params := make(map[string]string)
+	params["NextToken"] = nextToken
+
+	var resp listInboundShipmentItemsByNextTokenResponse
+	quota, err := api.fetchAndDecode("ListInboundShipmentItemsByNextToken", "/FulfillmentInboundShipment/2010-10-01", params, nil, &resp)
+
+	return resp.Result, quota, err
+}
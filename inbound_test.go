@@ -0,0 +1,70 @@
+package amazonmws
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestInboundShipmentPlanQuery(t *testing.T) {
+	caseQty := 12
+	req := CreateInboundShipmentPlanRequest{
+		ShipFromAddress: Address{
+			Name:                "Warehouse",
+			AddressLine1:        "1 Main St",
+			City:                "Seattle",
+			StateOrProvinceCode: "WA",
+			CountryCode:         "US",
+			PostalCode:          "98101",
+		},
+		LabelPrepPreference: "SELLER_LABEL",
+		Items: []InboundShipmentPlanRequestItem{
+			{SellerSKU: "SKU-1", Quantity: 24, QuantityInCase: &caseQty},
+			{SellerSKU: "SKU-2", Quantity: 3, PrepDetailsList: []PrepDetails{{PrepInstruction: "Polybagging", PrepOwner: "SELLER"}}},
+		},
+	}
+
+	params := make(map[string]string)
+	req.ShipFromAddress.appendQuery(params, "ShipFromAddress")
+	for i, item := range req.Items {
+		item.appendQuery(params, i)
+	}
+
+	assert.Equal(t, "Warehouse", params["ShipFromAddress.Name"])
+	assert.Equal(t, "98101", params["ShipFromAddress.PostalCode"])
+	assert.Equal(t, "SKU-1", params["InboundShipmentPlanRequestItems.member.1.SellerSKU"])
+	assert.Equal(t, "12", params["InboundShipmentPlanRequestItems.member.1.QuantityInCase"])
+	assert.Equal(t, "3", params["InboundShipmentPlanRequestItems.member.2.Quantity"])
+	assert.Equal(t, "Polybagging", params["InboundShipmentPlanRequestItems.member.2.PrepDetailsList.PrepDetails.1.PrepInstruction"])
+	_, hasLine2 := params["ShipFromAddress.AddressLine2"]
+	assert.False(t, hasLine2)
+}
+
+func TestTransportDocumentPDF(t *testing.T) {
+	pdf := []byte("%PDF-1.4 label")
+
+	var archive bytes.Buffer
+	w := zip.NewWriter(&archive)
+	f, err := w.Create("PackageLabels.pdf")
+	assert.Nil(t, err)
+	_, err = f.Write(pdf)
+	assert.Nil(t, err)
+	assert.Nil(t, w.Close())
+
+	sum := md5.Sum(archive.Bytes())
+	doc := TransportDocument{
+		PdfDocument: base64.StdEncoding.EncodeToString(archive.Bytes()),
+		Checksum:    base64.StdEncoding.EncodeToString(sum[:]),
+	}
+
+	result, err := doc.PDF()
+	assert.Nil(t, err)
+	assert.Equal(t, pdf, result)
+
+	doc.Checksum = "bogus"
+	_, err = doc.PDF()
+	assert.Equal(t, ErrChecksumMismatch, err)
+}
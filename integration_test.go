@@ -10,19 +10,7 @@ import (
 )
 
 func TestRequestReport(t *testing.T) {
-	err := godotenv.Load()
-	if err != nil {
-		panic(err)
-	}
-
-	api := AmazonMWSAPI{
-		AccessKey:     os.Getenv("ACCESS_KEY"),
-		SecretKey:     os.Getenv("SECRET_KEY"),
-		Host:          amazon.Marketplace(amazon.UnitedStates).MWSEndpoint(),
-		AuthToken:     "",
-		MarketplaceId: "ATVPDKIKX0DER",
-		SellerId:      os.Getenv("SELLER_ID"),
-	}
+	api := integrationAPI(t)
 
 	scenarios := []struct {
 		Name string
@@ -52,19 +40,7 @@ func TestRequestReport(t *testing.T) {
 }
 
 func TestGetReportRequestList(t *testing.T) {
-	err := godotenv.Load()
-	if err != nil {
-		panic(err)
-	}
-
-	api := AmazonMWSAPI{
-		AccessKey:     os.Getenv("ACCESS_KEY"),
-		SecretKey:     os.Getenv("SECRET_KEY"),
-		Host:          amazon.Marketplace(amazon.UnitedStates).MWSEndpoint(),
-		AuthToken:     "",
-		MarketplaceId: "ATVPDKIKX0DER",
-		SellerId:      os.Getenv("SELLER_ID"),
-	}
+	api := integrationAPI(t)
 
 	scenarios := []struct {
 		Name string
@@ -95,19 +71,7 @@ func TestGetReportRequestList(t *testing.T) {
 }
 
 func TestGetReport(t *testing.T) {
-	err := godotenv.Load()
-	if err != nil {
-		panic(err)
-	}
-
-	api := AmazonMWSAPI{
-		AccessKey:     os.Getenv("ACCESS_KEY"),
-		SecretKey:     os.Getenv("SECRET_KEY"),
-		Host:          amazon.Marketplace(amazon.UnitedStates).MWSEndpoint(),
-		AuthToken:     "",
-		MarketplaceId: "ATVPDKIKX0DER",
-		SellerId:      os.Getenv("SELLER_ID"),
-	}
+	api := integrationAPI(t)
 
 	scenarios := []struct {
 		Name string
@@ -134,6 +98,24 @@ func TestGetReport(t *testing.T) {
 	}
 }
 
+// integrationAPI returns a client for the US marketplace using the keys from
+// the environment or a .env file, and skips the test when there are none.
+func integrationAPI(t *testing.T) AmazonMWSAPI {
+	godotenv.Load()
+
+	credentials := EnvCredentials{}
+	if _, err := credentials.Retrieve(); err != nil {
+		t.Skip(err)
+	}
+
+	return AmazonMWSAPI{
+		Credentials:   credentials,
+		Host:          amazon.Marketplace(amazon.UnitedStates).MWSEndpoint(),
+		MarketplaceId: "ATVPDKIKX0DER",
+		SellerId:      os.Getenv("SELLER_ID"),
+	}
+}
+
 func String(s string) *string {
 	return &s
 }
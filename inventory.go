@@ -0,0 +1,111 @@
+package amazonmws
+
+import (
+	"strconv"
+	"time"
+)
+
+const (
+	ResponseGroupBasic    = "Basic"
+	ResponseGroupDetailed = "Detailed"
+)
+
+// Timepoint describes when inventory becomes available. TimepointType is
+// Immediately, DateTime or Unknown; DateTime is only set for DateTime.
+type Timepoint struct {
+	TimepointType string     `xml:"TimepointType"`
+	DateTime      *time.Time `xml:"DateTime"`
+}
+
+// InventorySupplyDetail is the quantity of an item in a single supply state,
+// such as InStock, Inbound or Transfer.
+type InventorySupplyDetail struct {
+	Quantity                int       `xml:"Quantity"`
+	SupplyType              string    `xml:"SupplyType"`
+	EarliestAvailableToPick Timepoint `xml:"EarliestAvailableToPick"`
+	LatestAvailableToPick   Timepoint `xml:"LatestAvailableToPick"`
+}
+
+type InventorySupply struct {
+	SellerSKU             string                  `xml:"SellerSKU"`
+	FNSKU                 string                  `xml:"FNSKU"`
+	ASIN                  string                  `xml:"ASIN"`
+	Condition             string                  `xml:"Condition"`
+	TotalSupplyQuantity   int                     `xml:"TotalSupplyQuantity"`
+	InStockSupplyQuantity int                     `xml:"InStockSupplyQuantity"`
+	EarliestAvailability  *Timepoint              `xml:"EarliestAvailability"`
+	SupplyDetail          []InventorySupplyDetail `xml:"SupplyDetail>member"`
+}
+
+// QuantityBySupplyType sums the supply detail quantities per SupplyType. It is
+// only populated for requests made with ResponseGroupDetailed.
+func (s InventorySupply) QuantityBySupplyType() map[string]int {
+	quantities := make(map[string]int)
+	for _, detail := range s.SupplyDetail {
+		quantities[detail.SupplyType] += detail.Quantity
+	}
+	return quantities
+}
+
+type ListInventorySupplyResult struct {
+	MarketplaceId       string            `xml:"MarketplaceId"`
+	NextToken           string            `xml:"NextToken"`
+	InventorySupplyList []InventorySupply `xml:"InventorySupplyList>member"`
+}
+
+type listInventorySupplyResponse struct {
+	Result           ListInventorySupplyResult `xml:"ListInventorySupplyResult"`
+	ResponseMetadata ResponseMetadata          `xml:"ResponseMetadata"`
+}
+
+type listInventorySupplyByNextTokenResponse struct {
+	Result           ListInventorySupplyResult `xml:"ListInventorySupplyByNextTokenResult"`
+	ResponseMetadata ResponseMetadata          `xml:"ResponseMetadata"`
+}
+
+// ListInventorySupplyRequest selects inventory either by SellerSkus (up to 50)
+// or by QueryStartDateTime. ResponseGroup defaults to ResponseGroupDetailed,
+// which includes the SupplyDetail breakdown, and MarketplaceId defaults to
+// the client's marketplace.
+type ListInventorySupplyRequest struct {
+	SellerSkus         []string
+	QueryStartDateTime *time.Time
+	ResponseGroup      string
+	MarketplaceId      *string
+}
+
+func (api AmazonMWSAPI) ListInventorySupply(req ListInventorySupplyRequest) (ListInventorySupplyResult, Quota, error) {
+	params := make(map[string]string)
+
+	for i, v := range req.SellerSkus {
+		params["SellerSkus.member."+strconv.Itoa(i+1)] = v
+	}
+	if req.QueryStartDateTime != nil {
+		params["QueryStartDateTime"] = formatTime(*req.QueryStartDateTime)
+	}
+	if req.ResponseGroup != "" {
+		params["ResponseGroup"] = req.ResponseGroup
+	} else {
+		params["ResponseGroup"] = ResponseGroupDetailed
+	}
+	if req.MarketplaceId != nil {
+		params["MarketplaceId"] = *req.MarketplaceId
+	} else {
+		params["MarketplaceId"] = api.MarketplaceId
+	}
+
+	var resp listInventorySupplyResponse
+	quota, err := api.fetchAndDecode("ListInventorySupply", "/FulfillmentInventory/2010-10-01", params, nil, &resp)
+
+	return resp.Result, quota, err
+}
+
+func (api AmazonMWSAPI) ListInventorySupplyByNextToken(nextToken string) (ListInventorySupplyResult, Quota, error) {
+	params := make(map[string]string)
+	params["NextToken"] = nextToken
+
+	var resp listInventorySupplyByNextTokenResponse
+	quota, err := api.fetchAndDecode("ListInventorySupplyByNextToken", "/FulfillmentInventory/2010-10-01", params, nil, &resp)
+
+	return resp.Result, quota, err
+}
@@ -0,0 +1,60 @@
+package amazonmws
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDecodeListInventorySupply(t *testing.T) {
+	body := `<?xml version="1.0"?>
+<ListInventorySupplyResponse xmlns="http://mws.amazonaws.com/FulfillmentInventory/2010-10-01/">
+  <ListInventorySupplyResult>
+    <MarketplaceId>ATVPDKIKX0DER</MarketplaceId>
+    <InventorySupplyList>
+      <member>
+        <Condition>NewItem</Condition>
+        <SupplyDetail>
+          <member>
+            <EarliestAvailableToPick><TimepointType>Immediately</TimepointType></EarliestAvailableToPick>
+            <LatestAvailableToPick><TimepointType>Immediately</TimepointType></LatestAvailableToPick>
+            <Quantity>7</Quantity>
+            <SupplyType>InStock</SupplyType>
+          </member>
+          <member>
+            <EarliestAvailableToPick><TimepointType>DateTime</TimepointType><DateTime>2010-11-03T00:00:00Z</DateTime></EarliestAvailableToPick>
+            <LatestAvailableToPick><TimepointType>Unknown</TimepointType></LatestAvailableToPick>
+            <Quantity>4</Quantity>
+            <SupplyType>Inbound</SupplyType>
+          </member>
+        </SupplyDetail>
+        <TotalSupplyQuantity>11</TotalSupplyQuantity>
+        <EarliestAvailability><TimepointType>Immediately</TimepointType></EarliestAvailability>
+        <FNSKU>X0000000ZZ</FNSKU>
+        <InStockSupplyQuantity>7</InStockSupplyQuantity>
+        <ASIN>B00000K3CQ</ASIN>
+        <SellerSKU>SampleSKU1</SellerSKU>
+      </member>
+    </InventorySupplyList>
+    <NextToken>NextToken</NextToken>
+  </ListInventorySupplyResult>
+  <ResponseMetadata><RequestId>e8698ffa-8e59-11df-9acb-230ae7a8b736</RequestId></ResponseMetadata>
+</ListInventorySupplyResponse>`
+
+	var resp listInventorySupplyResponse
+	err := decodeResponse(body, &resp)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "NextToken", resp.Result.NextToken)
+	assert.Len(t, resp.Result.InventorySupplyList, 1)
+
+	supply := resp.Result.InventorySupplyList[0]
+	assert.Equal(t, "SampleSKU1", supply.SellerSKU)
+	assert.Equal(t, 11, supply.TotalSupplyQuantity)
+	assert.Equal(t, "DateTime", supply.SupplyDetail[1].EarliestAvailableToPick.TimepointType)
+	assert.NotNil(t, supply.SupplyDetail[1].EarliestAvailableToPick.DateTime)
+	assert.Equal(t, map[string]int{"InStock": 7, "Inbound": 4}, supply.QuantityBySupplyType())
+}
+
+func TestListInventorySupplyByNextTokenSharesQuota(t *testing.T) {
+	assert.Equal(t, limiterFor("inventory-test", "ListInventorySupply", ""), limiterFor("inventory-test", "ListInventorySupplyByNextToken", ""))
+}
@@ -0,0 +1,251 @@
+package amazonmws
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// quotaSpec describes the throttling limits Amazon documents for an action:
+// the size of the request bucket and how long it takes to restore one item.
+type quotaSpec struct {
+	max     float64
+	restore time.Duration
+}
+
+var defaultQuotas = map[string]quotaSpec{
+	"GetLowestOfferListingsForASIN": {max: 20, restore: 100 * time.Millisecond},
+	"GetCompetitivePricingForASIN":  {max: 20, restore: 100 * time.Millisecond},
+	"GetMatchingProductForId":       {max: 20, restore: 200 * time.Millisecond},
+	"GetProductCategoriesForASIN":   {max: 20, restore: 5 * time.Second},
+	"GetServiceStatus":              {max: 2, restore: 5 * time.Minute},
+	"ListFinancialEventGroups":      {max: 30, restore: 2 * time.Second},
+	"ListFinancialEvents":           {max: 30, restore: 2 * time.Second},
+	"ListInventorySupply":           {max: 30, restore: 500 * time.Millisecond},
+	"ListMarketplaceParticipations": {max: 15, restore: time.Minute},
+}
+
+// sharedQuotas maps the actions that draw on another action's quota to that
+// action, such as the ByNextToken operations, which Amazon throttles together
+// with the operation that started the listing.
+var sharedQuotas = map[string]string{
+	"ListFinancialEventGroupsByNextToken": "ListFinancialEventGroups",
+	"ListFinancialEventsByNextToken":      "ListFinancialEvents",
+	"ListInventorySupplyByNextToken":      "ListInventorySupply",
+}
+
+// sectionQuotas are the actions Amazon grants a separate quota for in every
+// API section.
+var sectionQuotas = map[string]bool{
+	"GetServiceStatus": true,
+}
+
+// maxThrottleRetries is how often a throttled request is retried before the
+// throttling error is returned to the caller.
+const maxThrottleRetries = 3
+
+// fallbackQuota is used for actions without a documented entry in defaultQuotas.
+var fallbackQuota = quotaSpec{max: 10, restore: time.Second}
+
+// Limiter is a token bucket that paces requests for a single action so that
+// they stay within the quota Amazon grants to a seller.
+type Limiter struct {
+	mu      sync.Mutex
+	tokens  float64
+	max     float64
+	restore time.Duration
+	last    time.Time
+	paused  time.Time
+}
+
+// NewLimiter returns a full bucket holding max items that restores one item
+// every restore interval.
+func NewLimiter(max float64, restore time.Duration) *Limiter {
+	return &Limiter{
+		tokens:  max,
+		max:     max,
+		restore: restore,
+		last:    time.Now(),
+	}
+}
+
+// reserve takes n items from the bucket and reports how long the caller has
+// to wait before the items are actually available.
+func (l *Limiter) reserve(n float64) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens += float64(now.Sub(l.last)) / float64(l.restore)
+	if l.tokens > l.max {
+		l.tokens = l.max
+	}
+	l.last = now
+
+	if n > l.max {
+		n = l.max
+	}
+	l.tokens -= n
+
+	var wait time.Duration
+	if l.tokens < 0 {
+		wait = time.Duration(-l.tokens * float64(l.restore))
+	}
+	if until := l.paused.Sub(now); until > wait {
+		wait = until
+	}
+
+	return wait
+}
+
+// available reports how long it will be until n items can be taken, without
+// taking them.
+func (l *Limiter) available(n float64) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	tokens := l.tokens + float64(now.Sub(l.last))/float64(l.restore)
+	if tokens > l.max {
+		tokens = l.max
+	}
+	if n > l.max {
+		n = l.max
+	}
+
+	var wait time.Duration
+	if tokens < n {
+		wait = time.Duration((n - tokens) * float64(l.restore))
+	}
+	if until := l.paused.Sub(now); until > wait {
+		wait = until
+	}
+
+	return wait
+}
+
+// Wait blocks until n items are available or ctx is done.
+func (l *Limiter) Wait(ctx context.Context, n int) error {
+	wait := l.reserve(float64(n))
+	if wait <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Update pauses the limiter until the quota resets when q reports that the
+// hourly quota has been used up.
+func (l *Limiter) Update(q Quota) {
+	if !q.IsExpired() {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	until := time.Now().Add(q.RetryIn())
+	if until.After(l.paused) {
+		l.paused = until
+	}
+}
+
+// Backoff empties the bucket after Amazon has throttled a request, so that the
+// next caller waits for a full restore period.
+func (l *Limiter) Backoff() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.tokens > 0 {
+		l.tokens = 0
+	}
+	l.last = time.Now()
+}
+
+// fetchWithRetry calls fetch, retrying for as long as Amazon reports the
+// request as throttled. fetch is passed ctx carrying the attempt number, so
+// tracing can record it, and pacer with n, which the request waits on once it
+// has passed the middleware and is about to be sent; send also keeps pacer's
+// limiter up to date with the quota and throttling Amazon reports. Retries
+// are reported to tel.
+func fetchWithRetry(ctx context.Context, tel telemetry, action string, pacer pacer, n int, fetch func(ctx context.Context) (string, Quota, error)) (string, Quota, error) {
+	var body string
+	var quota Quota
+	var err error
+
+	for attempt := 0; attempt <= maxThrottleRetries; attempt++ {
+		body, quota, err = fetch(withPacing(withRetryAttempt(ctx, attempt), pacer, n))
+		if err == nil {
+			if mwsErr := parseErrorResponse(body); mwsErr != nil {
+				err = mwsErr
+			}
+		}
+		if err == nil {
+			return body, quota, nil
+		}
+
+		var mwsErr *MWSError
+		if !errors.As(err, &mwsErr) || !mwsErr.IsThrottled() {
+			break
+		}
+		if attempt < maxThrottleRetries {
+			tel.logger.Warn("mws retry", "action", action, "attempt", attempt+1, "error", err)
+			tel.metrics.IncRetry(action)
+		}
+	}
+
+	return body, quota, err
+}
+
+// RegisterQuota sets the quota used to pace action, for operations that have
+// no entry in the built-in table. It only affects limiters created afterwards.
+func RegisterQuota(action string, max float64, restore time.Duration) {
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+
+	defaultQuotas[action] = quotaSpec{max: max, restore: restore}
+}
+
+var (
+	limitersMu sync.Mutex
+	limiters   = make(map[string]*Limiter)
+)
+
+// limiterFor returns the limiter shared by every client of the given seller
+// for the given action. Quotas are granted per seller account, so copies of
+// AmazonMWSAPI for the same seller share a bucket, as do the actions in
+// sharedQuotas with the action they draw on. The section only matters
+// for actions in sectionQuotas, which get a bucket per section.
+func limiterFor(sellerId, action, section string) *Limiter {
+	if shared, ok := sharedQuotas[action]; ok {
+		action = shared
+	}
+	key := sellerId + "/" + action
+	if sectionQuotas[action] {
+		key += section
+	}
+
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+
+	l, ok := limiters[key]
+	if !ok {
+		spec, known := defaultQuotas[action]
+		if !known {
+			spec = fallbackQuota
+		}
+		l = NewLimiter(spec.max, spec.restore)
+		limiters[key] = l
+	}
+
+	return l
+}
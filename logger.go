@@ -0,0 +1,35 @@
+package amazonmws
+
+// Logger receives structured events about requests. Its methods take a
+// message followed by alternating keys and values, so a *slog.Logger can be
+// used directly.
+//
+// Events are logged at these levels:
+//   - Debug: "mws request" before and "mws response" after every request,
+//     and "mws cache hit" when ResponseCache answers instead
+//   - Warn: "mws throttled" when Amazon throttles a request and
+//     "mws retry" before it is retried
+//   - Error: "mws request failed" for transport errors and error responses
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(msg string, args ...interface{}) {}
+func (nopLogger) Info(msg string, args ...interface{})  {}
+func (nopLogger) Warn(msg string, args ...interface{})  {}
+func (nopLogger) Error(msg string, args ...interface{}) {}
+
+// logger returns api.Logger, or a logger that discards everything when it is
+// not set.
+func (api AmazonMWSAPI) logger() Logger {
+	if api.Logger == nil {
+		return nopLogger{}
+	}
+
+	return api.Logger
+}
@@ -0,0 +1,86 @@
+package amazonmws
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+type logEntry struct {
+	level string
+	msg   string
+	args  []interface{}
+}
+
+type recordingLogger struct {
+	mu      sync.Mutex
+	entries []logEntry
+}
+
+func (l *recordingLogger) log(level, msg string, args []interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, logEntry{level, msg, args})
+}
+
+func (l *recordingLogger) Debug(msg string, args ...interface{}) { l.log("debug", msg, args) }
+func (l *recordingLogger) Info(msg string, args ...interface{})  { l.log("info", msg, args) }
+func (l *recordingLogger) Warn(msg string, args ...interface{})  { l.log("warn", msg, args) }
+func (l *recordingLogger) Error(msg string, args ...interface{}) { l.log("error", msg, args) }
+
+func TestFetchWithRetryLogsRetries(t *testing.T) {
+	logger := &recordingLogger{}
+	limiter := NewLimiter(10, time.Millisecond)
+
+	calls := 0
+	body, _, err := fetchWithRetry(context.Background(), AmazonMWSAPI{Logger: logger}.telemetry(), "ListOrders", pacer{limiter: limiter}, 1, func(ctx context.Context) (string, Quota, error) {
+		calls++
+		if calls == 1 {
+			return `<ErrorResponse><Error><Type>Sender</Type><Code>RequestThrottled</Code><Message>Request is throttled</Message></Error><RequestID>r-1</RequestID></ErrorResponse>`, Quota{}, nil
+		}
+		return "<ListOrdersResponse/>", Quota{}, nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "<ListOrdersResponse/>", body)
+	assert.Len(t, logger.entries, 1)
+	assert.Equal(t, "warn", logger.entries[0].level)
+	assert.Equal(t, "mws retry", logger.entries[0].msg)
+	assert.Equal(t, []interface{}{"action", "ListOrders", "attempt", 1}, logger.entries[0].args[:4])
+}
+
+func TestUnknownSectionIsAnError(t *testing.T) {
+	api := AmazonMWSAPI{Host: "mws.amazonservices.com", Logger: &recordingLogger{}}
+
+	_, _, err := api.GetServiceStatus("/Unknown/2000-01-01")
+	assert.True(t, errors.Is(err, ErrUnknownSection))
+}
+
+func TestSendLogsSellerFromParams(t *testing.T) {
+	logger := &recordingLogger{}
+	onBehalf := func(next RoundTripper) RoundTripper {
+		return RoundTripperFunc(func(req *Request) (*Response, error) {
+			req.Params["SellerId"] = "A2SELLER"
+			return next.RoundTrip(req)
+		})
+	}
+	api := AmazonMWSAPI{Host: offlineHost, SellerId: "A1SELLER", Logger: logger, Middleware: []Middleware{onBehalf}}
+	// GetServiceStatus allows two requests every five minutes; leave the
+	// bucket full for repeated runs.
+	t.Cleanup(func() {
+		limitersMu.Lock()
+		defer limitersMu.Unlock()
+		delete(limiters, "A2SELLER/GetServiceStatus/Sellers/2011-07-01")
+	})
+
+	_, _, err := api.GetServiceStatus("/Sellers/2011-07-01")
+
+	assert.NotNil(t, err)
+	assert.Len(t, logger.entries, 2)
+	for _, entry := range logger.entries {
+		assert.Equal(t, []interface{}{"seller_id", "A2SELLER"}, entry.args[4:6])
+	}
+}
@@ -0,0 +1,100 @@
+package amazonmws
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrUnknownReceipt is returned when deleting a message whose receipt handle
+// is not, or no longer, valid.
+var ErrUnknownReceipt = errors.New("amazonmws: unknown receipt handle")
+
+type memoryMessage struct {
+	id        string
+	body      []byte
+	receipt   string
+	visibleAt time.Time
+}
+
+// MemoryQueue is an in-process Queue for tests. Like SQS, a received message
+// stays hidden for VisibilityTimeout and is delivered again unless deleted.
+type MemoryQueue struct {
+	VisibilityTimeout time.Duration
+
+	mu       sync.Mutex
+	messages []*memoryMessage
+	seq      int
+}
+
+// NewMemoryQueue returns an empty queue with a 30 second visibility timeout.
+func NewMemoryQueue() *MemoryQueue {
+	return &MemoryQueue{VisibilityTimeout: 30 * time.Second}
+}
+
+// Send enqueues body and returns its message ID.
+func (q *MemoryQueue) Send(body []byte) string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.seq++
+	id := strconv.Itoa(q.seq)
+	q.messages = append(q.messages, &memoryMessage{id: id, body: body})
+
+	return id
+}
+
+// Len returns the number of messages that have not been deleted.
+func (q *MemoryQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.messages)
+}
+
+func (q *MemoryQueue) Receive(ctx context.Context, max int) ([]QueueMessage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	var received []QueueMessage
+	for _, m := range q.messages {
+		if len(received) >= max {
+			break
+		}
+		if now.Before(m.visibleAt) {
+			continue
+		}
+
+		q.seq++
+		m.receipt = m.id + "-" + strconv.Itoa(q.seq)
+		m.visibleAt = now.Add(q.VisibilityTimeout)
+		received = append(received, QueueMessage{MessageId: m.id, ReceiptHandle: m.receipt, Body: m.body})
+	}
+
+	return received, nil
+}
+
+func (q *MemoryQueue) Delete(ctx context.Context, msg QueueMessage) error {
+	if msg.ReceiptHandle == "" {
+		return ErrUnknownReceipt
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for i, m := range q.messages {
+		if m.receipt == msg.ReceiptHandle {
+			q.messages = append(q.messages[:i], q.messages[i+1:]...)
+			return nil
+		}
+	}
+
+	return ErrUnknownReceipt
+}
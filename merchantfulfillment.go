@@ -0,0 +1,362 @@
+package amazonmws
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/md5"
+	"encoding/base64"
+	"io/ioutil"
+	"strconv"
+	"time"
+)
+
+// MerchantAddress is a ship-from or ship-to address in the Merchant
+// Fulfillment API.
+type MerchantAddress struct {
+	Name                string `xml:"Name"`
+	AddressLine1        string `xml:"AddressLine1"`
+	AddressLine2        string `xml:"AddressLine2"`
+	AddressLine3        string `xml:"AddressLine3"`
+	DistrictOrCounty    string `xml:"DistrictOrCounty"`
+	Email               string `xml:"Email"`
+	City                string `xml:"City"`
+	StateOrProvinceCode string `xml:"StateOrProvinceCode"`
+	PostalCode          string `xml:"PostalCode"`
+	CountryCode         string `xml:"CountryCode"`
+	Phone               string `xml:"Phone"`
+}
+
+func (a MerchantAddress) appendQuery(params map[string]string, prefix string) {
+	params[prefix+".Name"] = a.Name
+	params[prefix+".AddressLine1"] = a.AddressLine1
+	if a.AddressLine2 != "" {
+		params[prefix+".AddressLine2"] = a.AddressLine2
+	}
+	if a.AddressLine3 != "" {
+		params[prefix+".AddressLine3"] = a.AddressLine3
+	}
+	if a.DistrictOrCounty != "" {
+		params[prefix+".DistrictOrCounty"] = a.DistrictOrCounty
+	}
+	params[prefix+".Email"] = a.Email
+	params[prefix+".City"] = a.City
+	if a.StateOrProvinceCode != "" {
+		params[prefix+".StateOrProvinceCode"] = a.StateOrProvinceCode
+	}
+	params[prefix+".PostalCode"] = a.PostalCode
+	params[prefix+".CountryCode"] = a.CountryCode
+	params[prefix+".Phone"] = a.Phone
+}
+
+// CurrencyAmount is a currency value in the Merchant Fulfillment API.
+type CurrencyAmount struct {
+	CurrencyCode string `xml:"CurrencyCode"`
+	Amount       string `xml:"Amount"`
+}
+
+// PackageDimensions is either a custom box size or, when
+// PredefinedPackageDimensions is set, one of the carrier's standard packages.
+type PackageDimensions struct {
+	Length                      string `xml:"Length"`
+	Width                       string `xml:"Width"`
+	Height                      string `xml:"Height"`
+	Unit                        string `xml:"Unit"`
+	PredefinedPackageDimensions string `xml:"PredefinedPackageDimensions"`
+}
+
+func (d PackageDimensions) appendQuery(params map[string]string, prefix string) {
+	if d.PredefinedPackageDimensions != "" {
+		params[prefix+".PredefinedPackageDimensions"] = d.PredefinedPackageDimensions
+		return
+	}
+	params[prefix+".Length"] = d.Length
+	params[prefix+".Width"] = d.Width
+	params[prefix+".Height"] = d.Height
+	params[prefix+".Unit"] = d.Unit
+}
+
+type ShippingServiceOptions struct {
+	DeliveryExperience string          `xml:"DeliveryExperience"`
+	DeclaredValue      *CurrencyAmount `xml:"DeclaredValue"`
+	CarrierWillPickUp  bool            `xml:"CarrierWillPickUp"`
+	LabelFormat        string          `xml:"LabelFormat"`
+}
+
+type MerchantFulfillmentItem struct {
+	OrderItemId string `xml:"OrderItemId"`
+	Quantity    int    `xml:"Quantity"`
+}
+
+// ShipmentRequestDetails describes the order and package a shipping label is
+// requested for.
+type ShipmentRequestDetails struct {
+	AmazonOrderId          string
+	SellerOrderId          string
+	ItemList               []MerchantFulfillmentItem
+	ShipFromAddress        MerchantAddress
+	PackageDimensions      PackageDimensions
+	Weight                 Weight
+	MustArriveByDate       *time.Time
+	ShipDate               *time.Time
+	ShippingServiceOptions ShippingServiceOptions
+	CustomTextForLabel     string
+	StandardIdForLabel     string
+}
+
+func (d ShipmentRequestDetails) appendQuery(params map[string]string) {
+	prefix := "ShipmentRequestDetails"
+
+	params[prefix+".AmazonOrderId"] = d.AmazonOrderId
+	if d.SellerOrderId != "" {
+		params[prefix+".SellerOrderId"] = d.SellerOrderId
+	}
+	for i, item := range d.ItemList {
+		key := prefix + ".ItemList.Item." + strconv.Itoa(i+1)
+		params[key+".OrderItemId"] = item.OrderItemId
+		params[key+".Quantity"] = strconv.Itoa(item.Quantity)
+	}
+	d.ShipFromAddress.appendQuery(params, prefix+".ShipFromAddress")
+	d.PackageDimensions.appendQuery(params, prefix+".PackageDimensions")
+	d.Weight.appendQuery(params, prefix+".Weight")
+	if d.MustArriveByDate != nil {
+		params[prefix+".MustArriveByDate"] = formatTime(*d.MustArriveByDate)
+	}
+	if d.ShipDate != nil {
+		params[prefix+".ShipDate"] = formatTime(*d.ShipDate)
+	}
+
+	options := d.ShippingServiceOptions
+	params[prefix+".ShippingServiceOptions.DeliveryExperience"] = options.DeliveryExperience
+	params[prefix+".ShippingServiceOptions.CarrierWillPickUp"] = strconv.FormatBool(options.CarrierWillPickUp)
+	if options.DeclaredValue != nil {
+		params[prefix+".ShippingServiceOptions.DeclaredValue.CurrencyCode"] = options.DeclaredValue.CurrencyCode
+		params[prefix+".ShippingServiceOptions.DeclaredValue.Amount"] = options.DeclaredValue.Amount
+	}
+	if options.LabelFormat != "" {
+		params[prefix+".ShippingServiceOptions.LabelFormat"] = options.LabelFormat
+	}
+
+	if d.CustomTextForLabel != "" {
+		params[prefix+".LabelCustomization.CustomTextForLabel"] = d.CustomTextForLabel
+	}
+	if d.StandardIdForLabel != "" {
+		params[prefix+".LabelCustomization.StandardIdForLabel"] = d.StandardIdForLabel
+	}
+}
+
+type ShippingService struct {
+	ShippingServiceName            string                 `xml:"ShippingServiceName"`
+	CarrierName                    string                 `xml:"CarrierName"`
+	ShippingServiceId              string                 `xml:"ShippingServiceId"`
+	ShippingServiceOfferId         string                 `xml:"ShippingServiceOfferId"`
+	ShipDate                       time.Time              `xml:"ShipDate"`
+	EarliestEstimatedDeliveryDate  time.Time              `xml:"EarliestEstimatedDeliveryDate"`
+	LatestEstimatedDeliveryDate    time.Time              `xml:"LatestEstimatedDeliveryDate"`
+	Rate                           CurrencyAmount         `xml:"Rate"`
+	ShippingServiceOptions         ShippingServiceOptions `xml:"ShippingServiceOptions"`
+	AvailableLabelFormats          []string               `xml:"AvailableLabelFormats>LabelFormat"`
+	RequiresAdditionalSellerInputs bool                   `xml:"RequiresAdditionalSellerInputs"`
+}
+
+type GetEligibleShippingServicesResult struct {
+	ShippingServiceList                      []ShippingService `xml:"ShippingServiceList>ShippingService"`
+	TemporarilyUnavailableCarrierList        []string          `xml:"TemporarilyUnavailableCarrierList>TemporarilyUnavailableCarrier>CarrierName"`
+	TermsAndConditionsNotAcceptedCarrierList []string          `xml:"TermsAndConditionsNotAcceptedCarrierList>TermsAndConditionsNotAcceptedCarrier>CarrierName"`
+}
+
+type getEligibleShippingServicesResponse struct {
+	Result           GetEligibleShippingServicesResult `xml:"GetEligibleShippingServicesResult"`
+	ResponseMetadata ResponseMetadata                  `xml:"ResponseMetadata"`
+}
+
+func (api AmazonMWSAPI) GetEligibleShippingServices(details ShipmentRequestDetails) (GetEligibleShippingServicesResult, Quota, error) {
+	params := make(map[string]string)
+	details.appendQuery(params)
+
+	var resp getEligibleShippingServicesResponse
+	quota, err := api.fetchAndDecode("GetEligibleShippingServices", "/MerchantFulfillment/2015-06-01", params, nil, &resp)
+
+	return resp.Result, quota, err
+}
+
+type SellerInputDefinition struct {
+	IsRequired          bool     `xml:"IsRequired"`
+	DataType            string   `xml:"DataType"`
+	InputDisplayText    string   `xml:"InputDisplayText"`
+	InputTarget         string   `xml:"InputTarget"`
+	RestrictedSetValues []string `xml:"RestrictedSetValues>member"`
+}
+
+type AdditionalInputs struct {
+	AdditionalInputFieldName string                `xml:"AdditionalInputFieldName"`
+	SellerInputDefinition    SellerInputDefinition `xml:"SellerInputDefinition"`
+}
+
+type ItemLevelFields struct {
+	Asin             string             `xml:"Asin"`
+	AdditionalInputs []AdditionalInputs `xml:"AdditionalInputs>member"`
+}
+
+type GetAdditionalSellerInputsResult struct {
+	ShipmentLevelFields []AdditionalInputs `xml:"ShipmentLevelFields>member"`
+	ItemLevelFieldsList []ItemLevelFields  `xml:"ItemLevelFieldsList>member"`
+}
+
+type getAdditionalSellerInputsResponse struct {
+	Result           GetAdditionalSellerInputsResult `xml:"GetAdditionalSellerInputsResult"`
+	ResponseMetadata ResponseMetadata                `xml:"ResponseMetadata"`
+}
+
+// GetAdditionalSellerInputs returns the extra fields a carrier requires before
+// a label can be bought for a shipping service.
+func (api AmazonMWSAPI) GetAdditionalSellerInputs(orderId, shippingServiceId string, shipFrom MerchantAddress) (GetAdditionalSellerInputsResult, Quota, error) {
+	params := make(map[string]string)
+
+	params["OrderId"] = orderId
+	params["ShippingServiceId"] = shippingServiceId
+	shipFrom.appendQuery(params, "ShipFromAddress")
+
+	var resp getAdditionalSellerInputsResponse
+	quota, err := api.fetchAndDecode("GetAdditionalSellerInputs", "/MerchantFulfillment/2015-06-01", params, nil, &resp)
+
+	return resp.Result, quota, err
+}
+
+// FileContents is a label file. Contents is the base64 encoding of the
+// gzipped file, whose format is given by FileType.
+type FileContents struct {
+	Contents string `xml:"Contents"`
+	FileType string `xml:"FileType"`
+	Checksum string `xml:"Checksum"`
+}
+
+// Decode returns the printable label bytes, base64-decoded and gunzipped.
+// The checksum is accepted when it matches either the compressed or the
+// decompressed data.
+func (f FileContents) Decode() ([]byte, error) {
+	compressed, err := base64.StdEncoding.DecodeString(f.Contents)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if f.Checksum != "" && !checksumMatches(f.Checksum, compressed) && !checksumMatches(f.Checksum, data) {
+		return nil, ErrChecksumMismatch
+	}
+
+	return data, nil
+}
+
+func checksumMatches(checksum string, data []byte) bool {
+	sum := md5.Sum(data)
+	return base64.StdEncoding.EncodeToString(sum[:]) == checksum
+}
+
+type LabelDimensions struct {
+	Length string `xml:"Length"`
+	Width  string `xml:"Width"`
+	Unit   string `xml:"Unit"`
+}
+
+type Label struct {
+	CustomTextForLabel string          `xml:"CustomTextForLabel"`
+	Dimensions         LabelDimensions `xml:"Dimensions"`
+	FileContents       FileContents    `xml:"FileContents"`
+	LabelFormat        string          `xml:"LabelFormat"`
+	StandardIdForLabel string          `xml:"StandardIdForLabel"`
+}
+
+// MerchantShipment is a shipment created through the Merchant Fulfillment API.
+type MerchantShipment struct {
+	ShipmentId        string                    `xml:"ShipmentId"`
+	AmazonOrderId     string                    `xml:"AmazonOrderId"`
+	SellerOrderId     string                    `xml:"SellerOrderId"`
+	ItemList          []MerchantFulfillmentItem `xml:"ItemList>Item"`
+	ShipFromAddress   MerchantAddress           `xml:"ShipFromAddress"`
+	ShipToAddress     MerchantAddress           `xml:"ShipToAddress"`
+	PackageDimensions PackageDimensions         `xml:"PackageDimensions"`
+	Weight            Weight                    `xml:"Weight"`
+	Insurance         CurrencyAmount            `xml:"Insurance"`
+	ShippingService   ShippingService           `xml:"ShippingService"`
+	Label             Label                     `xml:"Label"`
+	Status            string                    `xml:"Status"`
+	TrackingId        string                    `xml:"TrackingId"`
+	CreatedDate       time.Time                 `xml:"CreatedDate"`
+	LastUpdatedDate   time.Time                 `xml:"LastUpdatedDate"`
+}
+
+type createShipmentResponse struct {
+	Shipment         MerchantShipment `xml:"CreateShipmentResult>Shipment"`
+	ResponseMetadata ResponseMetadata `xml:"ResponseMetadata"`
+}
+
+type getShipmentResponse struct {
+	Shipment         MerchantShipment `xml:"GetShipmentResult>Shipment"`
+	ResponseMetadata ResponseMetadata `xml:"ResponseMetadata"`
+}
+
+type cancelShipmentResponse struct {
+	Shipment         MerchantShipment `xml:"CancelShipmentResult>Shipment"`
+	ResponseMetadata ResponseMetadata `xml:"ResponseMetadata"`
+}
+
+type CreateShipmentRequest struct {
+	ShipmentRequestDetails      ShipmentRequestDetails
+	ShippingServiceId           string
+	ShippingServiceOfferId      string
+	HazmatType                  string
+	IncludePackingSlipWithLabel bool
+}
+
+// CreateShipment buys a shipping label. The label is in
+// Shipment.Label.FileContents; call Decode on it to get the printable file.
+func (api AmazonMWSAPI) CreateShipment(req CreateShipmentRequest) (MerchantShipment, Quota, error) {
+	params := make(map[string]string)
+
+	req.ShipmentRequestDetails.appendQuery(params)
+	params["ShippingServiceId"] = req.ShippingServiceId
+	if req.ShippingServiceOfferId != "" {
+		params["ShippingServiceOfferId"] = req.ShippingServiceOfferId
+	}
+	if req.HazmatType != "" {
+		params["HazmatType"] = req.HazmatType
+	}
+	if req.IncludePackingSlipWithLabel {
+		params["LabelFormatOption.IncludePackingSlipWithLabel"] = "true"
+	}
+
+	var resp createShipmentResponse
+	quota, err := api.fetchAndDecode("CreateShipment", "/MerchantFulfillment/2015-06-01", params, nil, &resp)
+
+	return resp.Shipment, quota, err
+}
+
+func (api AmazonMWSAPI) GetShipment(shipmentId string) (MerchantShipment, Quota, error) {
+	params := make(map[string]string)
+	params["ShipmentId"] = shipmentId
+
+	var resp getShipmentResponse
+	quota, err := api.fetchAndDecode("GetShipment", "/MerchantFulfillment/2015-06-01", params, nil, &resp)
+
+	return resp.Shipment, quota, err
+}
+
+func (api AmazonMWSAPI) CancelShipment(shipmentId string) (MerchantShipment, Quota, error) {
+	params := make(map[string]string)
+	params["ShipmentId"] = shipmentId
+
+	var resp cancelShipmentResponse
+	quota, err := api.fetchAndDecode("CancelShipment", "/MerchantFulfillment/2015-06-01", params, nil, &resp)
+
+	return resp.Shipment, quota, err
+}
@@ -0,0 +1,83 @@
+package amazonmws
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/md5"
+	"encoding/base64"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDecodeCreateShipmentLabel(t *testing.T) {
+	label := []byte("^XA^FO50,50^ADN,36,20^FDSHIPPING LABEL^FS^XZ")
+
+	var compressed bytes.Buffer
+	w := gzip.NewWriter(&compressed)
+	_, err := w.Write(label)
+	assert.Nil(t, err)
+	assert.Nil(t, w.Close())
+
+	sum := md5.Sum(compressed.Bytes())
+	body := `<CreateShipmentResponse xmlns="https://mws.amazonservices.com/MerchantFulfillment/2015-06-01">
+  <CreateShipmentResult>
+    <Shipment>
+      <ShipmentId>6f77095e-9f75-47eb-aaab-a42d5428fa1a</ShipmentId>
+      <AmazonOrderId>903-5563053-5647845</AmazonOrderId>
+      <Weight><Value>10</Value><Unit>oz</Unit></Weight>
+      <ShippingService>
+        <CarrierName>USPS</CarrierName>
+        <ShippingServiceId>USPS_PTP_PRI_LFRB</ShippingServiceId>
+        <Rate><CurrencyCode>USD</CurrencyCode><Amount>5.80</Amount></Rate>
+      </ShippingService>
+      <Label>
+        <Dimensions><Length>6.00000</Length><Width>4.00000</Width><Unit>inches</Unit></Dimensions>
+        <FileContents>
+          <Contents>` + base64.StdEncoding.EncodeToString(compressed.Bytes()) + `</Contents>
+          <FileType>application/zpl</FileType>
+          <Checksum>` + base64.StdEncoding.EncodeToString(sum[:]) + `</Checksum>
+        </FileContents>
+      </Label>
+      <Status>Purchased</Status>
+      <TrackingId>9405536897846173912345</TrackingId>
+    </Shipment>
+  </CreateShipmentResult>
+  <ResponseMetadata><RequestId>5e5e5694-8e76-11df-929f-87c80302f8f6</RequestId></ResponseMetadata>
+</CreateShipmentResponse>`
+
+	var resp createShipmentResponse
+	err = decodeResponse(body, &resp)
+	assert.Nil(t, err)
+
+	shipment := resp.Shipment
+	assert.Equal(t, "Purchased", shipment.Status)
+	assert.Equal(t, CurrencyAmount{CurrencyCode: "USD", Amount: "5.80"}, shipment.ShippingService.Rate)
+	assert.Equal(t, Weight{Unit: "oz", Value: "10"}, shipment.Weight)
+	assert.Equal(t, "application/zpl", shipment.Label.FileContents.FileType)
+
+	data, err := shipment.Label.FileContents.Decode()
+	assert.Nil(t, err)
+	assert.Equal(t, label, data)
+}
+
+func TestShipmentRequestDetailsQuery(t *testing.T) {
+	params := make(map[string]string)
+
+	ShipmentRequestDetails{
+		AmazonOrderId:     "903-5563053-5647845",
+		ItemList:          []MerchantFulfillmentItem{{OrderItemId: "52986411826454", Quantity: 1}},
+		ShipFromAddress:   MerchantAddress{Name: "John Doe", AddressLine1: "1234 Westlake Ave", Email: "example@example.com", City: "Seattle", StateOrProvinceCode: "WA", PostalCode: "98121", CountryCode: "US", Phone: "2061234567"},
+		PackageDimensions: PackageDimensions{PredefinedPackageDimensions: "USPS_Card"},
+		Weight:            Weight{Unit: "oz", Value: "10"},
+		ShippingServiceOptions: ShippingServiceOptions{
+			DeliveryExperience: "DeliveryConfirmationWithoutSignature",
+			CarrierWillPickUp:  false,
+		},
+	}.appendQuery(params)
+
+	assert.Equal(t, "52986411826454", params["ShipmentRequestDetails.ItemList.Item.1.OrderItemId"])
+	assert.Equal(t, "USPS_Card", params["ShipmentRequestDetails.PackageDimensions.PredefinedPackageDimensions"])
+	assert.Equal(t, "", params["ShipmentRequestDetails.PackageDimensions.Length"])
+	assert.Equal(t, "false", params["ShipmentRequestDetails.ShippingServiceOptions.CarrierWillPickUp"])
+	assert.Equal(t, "98121", params["ShipmentRequestDetails.ShipFromAddress.PostalCode"])
+}
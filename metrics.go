@@ -0,0 +1,246 @@
+package amazonmws
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metrics receives measurements for every request. Status is the HTTP status
+// code, or "error" when no response was received.
+type Metrics interface {
+	ObserveRequest(action, section, status string, duration time.Duration)
+	IncThrottle(action, sellerId string)
+	IncRetry(action string)
+	SetQuotaRemaining(action, sellerId string, remaining float64)
+}
+
+type nopMetrics struct{}
+
+func (nopMetrics) ObserveRequest(action, section, status string, duration time.Duration) {}
+func (nopMetrics) IncThrottle(action, sellerId string)                                   {}
+func (nopMetrics) IncRetry(action string)                                                {}
+func (nopMetrics) SetQuotaRemaining(action, sellerId string, remaining float64)          {}
+
+// telemetry bundles the hooks a request reports to, with defaults filled in
+// for the ones the client does not set.
+type telemetry struct {
+	logger  Logger
+	metrics Metrics
+	tracer  Tracer
+}
+
+func (api AmazonMWSAPI) telemetry() telemetry {
+	t := telemetry{logger: api.logger(), metrics: api.Metrics, tracer: api.Tracer}
+	if t.metrics == nil {
+		t.metrics = nopMetrics{}
+	}
+	if t.tracer == nil {
+		t.tracer = nopTracer{}
+	}
+
+	return t
+}
+
+// DefaultLatencyBuckets are the upper bounds, in seconds, of the request
+// latency histogram.
+var DefaultLatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+type histogram struct {
+	bounds []float64
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// PrometheusMetrics implements Metrics and serves the collected values in the
+// Prometheus text exposition format, so it can be scraped directly or mounted
+// next to an existing registry's handler:
+//
+//	metrics := amazonmws.NewPrometheusMetrics()
+//	api.Metrics = metrics
+//	http.Handle("/metrics/mws", metrics)
+//
+// It exports mws_requests_total and mws_request_duration_seconds by action,
+// section and status, mws_throttles_total and mws_quota_remaining by action
+// and seller, and mws_retries_total by action.
+type PrometheusMetrics struct {
+	Buckets []float64
+
+	mu         sync.Mutex
+	requests   map[[3]string]uint64
+	durations  map[[3]string]*histogram
+	throttles  map[[2]string]uint64
+	retries    map[string]uint64
+	quotaGauge map[[2]string]float64
+}
+
+func NewPrometheusMetrics() *PrometheusMetrics {
+	return &PrometheusMetrics{
+		Buckets:    DefaultLatencyBuckets,
+		requests:   make(map[[3]string]uint64),
+		durations:  make(map[[3]string]*histogram),
+		throttles:  make(map[[2]string]uint64),
+		retries:    make(map[string]uint64),
+		quotaGauge: make(map[[2]string]float64),
+	}
+}
+
+func (m *PrometheusMetrics) ObserveRequest(action, section, status string, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := [3]string{action, section, status}
+	m.requests[key]++
+
+	h, ok := m.durations[key]
+	if !ok {
+		h = &histogram{bounds: m.Buckets, counts: make([]uint64, len(m.Buckets))}
+		m.durations[key] = h
+	}
+	seconds := duration.Seconds()
+	for i, bound := range h.bounds {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+func (m *PrometheusMetrics) IncThrottle(action, sellerId string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.throttles[[2]string{action, sellerId}]++
+}
+
+func (m *PrometheusMetrics) IncRetry(action string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.retries[action]++
+}
+
+func (m *PrometheusMetrics) SetQuotaRemaining(action, sellerId string, remaining float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.quotaGauge[[2]string{action, sellerId}] = remaining
+}
+
+func (m *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WriteTo(w)
+}
+
+// WriteTo writes every metric in the Prometheus text exposition format.
+func (m *PrometheusMetrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+
+	b.WriteString("# HELP mws_requests_total MWS requests sent.\n# TYPE mws_requests_total counter\n")
+	for _, key := range sortedKeys3(m.requests) {
+		fmt.Fprintf(&b, "mws_requests_total{%s} %d\n", labels3(key), m.requests[key])
+	}
+
+	b.WriteString("# HELP mws_request_duration_seconds MWS request latency.\n# TYPE mws_request_duration_seconds histogram\n")
+	for _, key := range sortedKeys3(m.requests) {
+		h := m.durations[key]
+		for i, bound := range h.bounds {
+			fmt.Fprintf(&b, "mws_request_duration_seconds_bucket{%s,le=\"%s\"} %d\n", labels3(key), formatFloat(bound), h.counts[i])
+		}
+		fmt.Fprintf(&b, "mws_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels3(key), h.count)
+		fmt.Fprintf(&b, "mws_request_duration_seconds_sum{%s} %s\n", labels3(key), formatFloat(h.sum))
+		fmt.Fprintf(&b, "mws_request_duration_seconds_count{%s} %d\n", labels3(key), h.count)
+	}
+
+	b.WriteString("# HELP mws_throttles_total MWS requests rejected as throttled.\n# TYPE mws_throttles_total counter\n")
+	for _, key := range sortedKeys2(m.throttles) {
+		fmt.Fprintf(&b, "mws_throttles_total{action=%s,seller_id=%s} %d\n", quoteLabel(key[0]), quoteLabel(key[1]), m.throttles[key])
+	}
+
+	b.WriteString("# HELP mws_retries_total MWS requests retried after throttling.\n# TYPE mws_retries_total counter\n")
+	actions := make([]string, 0, len(m.retries))
+	for action := range m.retries {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	for _, action := range actions {
+		fmt.Fprintf(&b, "mws_retries_total{action=%s} %d\n", quoteLabel(action), m.retries[action])
+	}
+
+	b.WriteString("# HELP mws_quota_remaining Last x-mws-quota-remaining reported by Amazon.\n# TYPE mws_quota_remaining gauge\n")
+	quotaKeys := make([][2]string, 0, len(m.quotaGauge))
+	for key := range m.quotaGauge {
+		quotaKeys = append(quotaKeys, key)
+	}
+	sortKeys2(quotaKeys)
+	for _, key := range quotaKeys {
+		fmt.Fprintf(&b, "mws_quota_remaining{action=%s,seller_id=%s} %s\n", quoteLabel(key[0]), quoteLabel(key[1]), formatFloat(m.quotaGauge[key]))
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func labels3(key [3]string) string {
+	return "action=" + quoteLabel(key[0]) + ",section=" + quoteLabel(key[1]) + ",status=" + quoteLabel(key[2])
+}
+
+func quoteLabel(value string) string {
+	value = strings.Replace(value, `\`, `\\`, -1)
+	value = strings.Replace(value, `"`, `\"`, -1)
+	value = strings.Replace(value, "\n", `\n`, -1)
+
+	return `"` + value + `"`
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func sortedKeys3(m map[[3]string]uint64) [][3]string {
+	keys := make([][3]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		if a[1] != b[1] {
+			return a[1] < b[1]
+		}
+		return a[2] < b[2]
+	})
+
+	return keys
+}
+
+func sortedKeys2(m map[[2]string]uint64) [][2]string {
+	keys := make([][2]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sortKeys2(keys)
+
+	return keys
+}
+
+func sortKeys2(keys [][2]string) {
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+}
@@ -0,0 +1,56 @@
+package amazonmws
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestPrometheusMetrics(t *testing.T) {
+	metrics := NewPrometheusMetrics()
+	metrics.Buckets = []float64{0.1, 1}
+
+	metrics.ObserveRequest("ListOrders", "/Orders/2013-09-01", "200", 50*time.Millisecond)
+	metrics.ObserveRequest("ListOrders", "/Orders/2013-09-01", "200", 500*time.Millisecond)
+	metrics.ObserveRequest("ListOrders", "/Orders/2013-09-01", "503", 2*time.Second)
+	metrics.IncThrottle("ListOrders", "A1SELLER")
+	metrics.SetQuotaRemaining("ListOrders", "A1SELLER", 3)
+	metrics.SetQuotaRemaining("ListOrders", "A1SELLER", 2)
+	metrics.SetQuotaRemaining("ListOrders", `A"2`, 5)
+
+	var buf bytes.Buffer
+	_, err := metrics.WriteTo(&buf)
+	assert.Nil(t, err)
+
+	out := buf.String()
+	assert.Contains(t, out, `mws_requests_total{action="ListOrders",section="/Orders/2013-09-01",status="200"} 2`+"\n")
+	assert.Contains(t, out, `mws_request_duration_seconds_bucket{action="ListOrders",section="/Orders/2013-09-01",status="200",le="0.1"} 1`+"\n")
+	assert.Contains(t, out, `mws_request_duration_seconds_bucket{action="ListOrders",section="/Orders/2013-09-01",status="200",le="1"} 2`+"\n")
+	assert.Contains(t, out, `mws_request_duration_seconds_bucket{action="ListOrders",section="/Orders/2013-09-01",status="503",le="+Inf"} 1`+"\n")
+	assert.Contains(t, out, `mws_request_duration_seconds_sum{action="ListOrders",section="/Orders/2013-09-01",status="503"} 2`+"\n")
+	assert.Contains(t, out, `mws_throttles_total{action="ListOrders",seller_id="A1SELLER"} 1`+"\n")
+	assert.Contains(t, out, `mws_quota_remaining{action="ListOrders",seller_id="A1SELLER"} 2`+"\n")
+	assert.Contains(t, out, `mws_quota_remaining{action="ListOrders",seller_id="A\"2"} 5`+"\n")
+	assert.Contains(t, out, "# TYPE mws_request_duration_seconds histogram\n")
+}
+
+func TestFetchWithRetryCountsRetries(t *testing.T) {
+	metrics := NewPrometheusMetrics()
+	limiter := NewLimiter(10, time.Millisecond)
+
+	calls := 0
+	_, _, err := fetchWithRetry(context.Background(), AmazonMWSAPI{Metrics: metrics}.telemetry(), "ListOrders", pacer{limiter: limiter}, 1, func(ctx context.Context) (string, Quota, error) {
+		calls++
+		if calls < 3 {
+			return `<ErrorResponse><Error><Code>RequestThrottled</Code></Error></ErrorResponse>`, Quota{}, nil
+		}
+		return "<ListOrdersResponse/>", Quota{}, nil
+	})
+	assert.Nil(t, err)
+
+	var buf bytes.Buffer
+	metrics.WriteTo(&buf)
+	assert.Contains(t, buf.String(), `mws_retries_total{action="ListOrders"} 2`+"\n")
+}
@@ -0,0 +1,88 @@
+package amazonmws
+
+import (
+	"context"
+	"net/http"
+)
+
+// Request is an MWS call on its way through the middleware chain. Params
+// already hold the auth parameters, SellerId and Timestamp; the Signature is
+// computed from Params after the chain, so middleware may change them and
+// Body freely. Header is added to the HTTP request.
+type Request struct {
+	Context context.Context
+	Action  string
+	Section string
+	Version string
+	Params  map[string]string
+	Body    []byte
+	Header  http.Header
+
+	secretKey string
+}
+
+// Response is what Amazon answered to a Request. Body is returned to the
+// caller and Quota paces later requests, so middleware may replace either.
+type Response struct {
+	StatusCode int    `json:"status_code"`
+	Body       string `json:"body"`
+	Quota      Quota  `json:"quota"`
+}
+
+// RoundTripper sends a Request and returns its Response, in the same way as
+// http.RoundTripper. The innermost one signs the request and sends it to
+// Amazon.
+type RoundTripper interface {
+	RoundTrip(req *Request) (*Response, error)
+}
+
+// RoundTripperFunc lets an ordinary function be used as a RoundTripper.
+type RoundTripperFunc func(req *Request) (*Response, error)
+
+func (f RoundTripperFunc) RoundTrip(req *Request) (*Response, error) {
+	return f(req)
+}
+
+// Middleware wraps the RoundTripper that sends a request. It may change the
+// request before calling next, inspect or replace the response afterwards,
+// or answer without calling next at all:
+//
+//	func TenantTag(tenant string) amazonmws.Middleware {
+//		return func(next amazonmws.RoundTripper) amazonmws.RoundTripper {
+//			return amazonmws.RoundTripperFunc(func(req *amazonmws.Request) (*amazonmws.Response, error) {
+//				req.Header.Set("X-Tenant", tenant)
+//				return next.RoundTrip(req)
+//			})
+//		}
+//	}
+type Middleware func(next RoundTripper) RoundTripper
+
+// Chain composes middlewares into one. The first sees the request first and
+// the response last.
+func Chain(middlewares ...Middleware) Middleware {
+	return func(next RoundTripper) RoundTripper {
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			next = middlewares[i](next)
+		}
+
+		return next
+	}
+}
+
+// roundTripper returns api.Middleware wrapped around send.
+func (api AmazonMWSAPI) roundTripper() RoundTripper {
+	return Chain(api.Middleware...)(RoundTripperFunc(api.send))
+}
+
+// matchKey identifies a request by everything but the ignored parameters, for
+// middleware that treats equal requests alike.
+func matchKey(action, section string, params map[string]string, body string, ignored map[string]bool) string {
+	matched := make(map[string]string, len(params))
+	for k, v := range params {
+		if !ignored[k] {
+			matched[k] = v
+		}
+	}
+
+	return action + " " + section + "?" + CanonicalQuery(matched) + "\n" + body
+}
@@ -0,0 +1,89 @@
+package amazonmws
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestChainOrder(t *testing.T) {
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next RoundTripper) RoundTripper {
+			return RoundTripperFunc(func(req *Request) (*Response, error) {
+				calls = append(calls, name+" request")
+				resp, err := next.RoundTrip(req)
+				calls = append(calls, name+" response")
+				return resp, err
+			})
+		}
+	}
+	send := RoundTripperFunc(func(req *Request) (*Response, error) {
+		calls = append(calls, "send")
+		return &Response{StatusCode: 200}, nil
+	})
+
+	_, err := Chain(trace("outer"), trace("inner"))(send).RoundTrip(&Request{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"outer request", "inner request", "send", "inner response", "outer response"}, calls)
+}
+
+func TestMiddlewareSeesParamsBeforeSigning(t *testing.T) {
+	tag := func(next RoundTripper) RoundTripper {
+		return RoundTripperFunc(func(req *Request) (*Response, error) {
+			req.Params["Tenant"] = "acme"
+			req.Header.Set("X-Tenant", "acme")
+			return next.RoundTrip(req)
+		})
+	}
+
+	var seen *Request
+	stub := func(next RoundTripper) RoundTripper {
+		return RoundTripperFunc(func(req *Request) (*Response, error) {
+			seen = req
+			return &Response{
+				StatusCode: 200,
+				Body:       `<GetServiceStatusResponse><GetServiceStatusResult><Status>GREEN</Status></GetServiceStatusResult></GetServiceStatusResponse>`,
+				Quota:      Quota{MwsQuotaMax: 2, MwsQuotaRemaining: 1},
+			}, nil
+		})
+	}
+
+	api := AmazonMWSAPI{
+		AccessKey:  "AKIDEXAMPLE",
+		SecretKey:  "secret",
+		Host:       "mws.amazonservices.com",
+		SellerId:   "A1SELLER",
+		Middleware: []Middleware{tag, stub},
+	}
+	status, quota, err := api.GetServiceStatus("/Sellers/2011-07-01")
+
+	assert.Nil(t, err)
+	assert.Equal(t, ServiceStatusGreen, status.Status)
+	assert.Equal(t, float64(1), quota.MwsQuotaRemaining)
+
+	assert.Equal(t, "GetServiceStatus", seen.Action)
+	assert.Equal(t, "/Sellers/2011-07-01", seen.Section)
+	assert.Equal(t, "2011-07-01", seen.Version)
+	assert.Equal(t, "acme", seen.Params["Tenant"])
+	assert.Equal(t, "AKIDEXAMPLE", seen.Params["AWSAccessKeyId"])
+	assert.Equal(t, "A1SELLER", seen.Params["SellerId"])
+	assert.NotEmpty(t, seen.Params["Timestamp"])
+	assert.NotContains(t, seen.Params, "Signature")
+	assert.Equal(t, "acme", seen.Header.Get("X-Tenant"))
+}
+
+// amazonReturning is a middleware that answers every request with body
+// instead of sending it, recording the requests it saw in seen.
+func amazonReturning(body string, seen *[]*Request) Middleware {
+	return func(next RoundTripper) RoundTripper {
+		return RoundTripperFunc(func(req *Request) (*Response, error) {
+			*seen = append(*seen, req)
+			return &Response{StatusCode: 200, Body: body}, nil
+		})
+	}
+}
+
+// offlineHost is read by GenerateAmazonUrlPost as a path rather than a host,
+// so requests sent to it fail before they leave the machine.
+const offlineHost = "mws.amazonservices.com"
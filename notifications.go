@@ -0,0 +1,157 @@
+package amazonmws
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+const (
+	NotificationTypeAnyOfferChanged          = "AnyOfferChanged"
+	NotificationTypeFulfillmentOrderStatus   = "FulfillmentOrderStatus"
+	NotificationTypeReportProcessingFinished = "ReportProcessingFinished"
+	NotificationTypeFeedProcessingFinished   = "FeedProcessingFinished"
+	NotificationTypeTest                     = "Test"
+)
+
+type NotificationMetaData struct {
+	NotificationType string    `xml:"NotificationType"`
+	PayloadVersion   string    `xml:"PayloadVersion"`
+	UniqueId         string    `xml:"UniqueId"`
+	PublishTime      time.Time `xml:"PublishTime"`
+	SellerId         string    `xml:"SellerId"`
+	MarketplaceId    string    `xml:"MarketplaceId"`
+}
+
+type OfferChangeTrigger struct {
+	MarketplaceId     string    `xml:"MarketplaceId"`
+	ASIN              string    `xml:"ASIN"`
+	ItemCondition     string    `xml:"ItemCondition"`
+	TimeOfOfferChange time.Time `xml:"TimeOfOfferChange"`
+}
+
+type OfferCount struct {
+	Condition          string `xml:"condition,attr"`
+	FulfillmentChannel string `xml:"fulfillmentChannel,attr"`
+	Count              int    `xml:",chardata"`
+}
+
+type NotificationPrice struct {
+	Condition          string          `xml:"condition,attr"`
+	FulfillmentChannel string          `xml:"fulfillmentChannel,attr"`
+	LandedPrice        *CurrencyAmount `xml:"LandedPrice"`
+	ListingPrice       CurrencyAmount  `xml:"ListingPrice"`
+	Shipping           *CurrencyAmount `xml:"Shipping"`
+}
+
+type SalesRank struct {
+	ProductCategoryId string `xml:"ProductCategoryId"`
+	Rank              int    `xml:"Rank"`
+}
+
+type OfferSummary struct {
+	NumberOfOffers                  []OfferCount        `xml:"NumberOfOffers>OfferCount"`
+	LowestPrices                    []NotificationPrice `xml:"LowestPrices>LowestPrice"`
+	BuyBoxPrices                    []NotificationPrice `xml:"BuyBoxPrices>BuyBoxPrice"`
+	ListPrice                       *CurrencyAmount     `xml:"ListPrice"`
+	SuggestedLowerPricePlusShipping *CurrencyAmount     `xml:"SuggestedLowerPricePlusShipping"`
+	SalesRankings                   []SalesRank         `xml:"SalesRankings>SalesRank"`
+	NumberOfBuyBoxEligibleOffers    []OfferCount        `xml:"NumberOfBuyBoxEligibleOffers>OfferCount"`
+	CompetitivePriceThreshold       *CurrencyAmount     `xml:"CompetitivePriceThreshold"`
+}
+
+type SellerFeedbackRating struct {
+	SellerPositiveFeedbackRating float64 `xml:"SellerPositiveFeedbackRating"`
+	FeedbackCount                int     `xml:"FeedbackCount"`
+}
+
+type ShippingTime struct {
+	MinimumHours     int    `xml:"minimumHours,attr"`
+	MaximumHours     int    `xml:"maximumHours,attr"`
+	AvailabilityType string `xml:"availabilityType,attr"`
+	AvailableDate    string `xml:"availableDate,attr"`
+}
+
+type ShipsFrom struct {
+	Country string `xml:"Country"`
+	State   string `xml:"State"`
+}
+
+type PrimeInformation struct {
+	IsPrime         bool `xml:"IsPrime"`
+	IsNationalPrime bool `xml:"IsNationalPrime"`
+}
+
+type NotificationOffer struct {
+	SellerId             string               `xml:"SellerId"`
+	SubCondition         string               `xml:"SubCondition"`
+	SellerFeedbackRating SellerFeedbackRating `xml:"SellerFeedbackRating"`
+	ShippingTime         ShippingTime         `xml:"ShippingTime"`
+	ListingPrice         CurrencyAmount       `xml:"ListingPrice"`
+	Shipping             CurrencyAmount       `xml:"Shipping"`
+	ShipsFrom            *ShipsFrom           `xml:"ShipsFrom"`
+	IsFulfilledByAmazon  bool                 `xml:"IsFulfilledByAmazon"`
+	IsBuyBoxWinner       bool                 `xml:"IsBuyBoxWinner"`
+	PrimeInformation     *PrimeInformation    `xml:"PrimeInformation"`
+	IsFeaturedMerchant   bool                 `xml:"IsFeaturedMerchant"`
+	ShipsDomestically    bool                 `xml:"ShipsDomestically"`
+}
+
+type AnyOfferChangedNotification struct {
+	OfferChangeTrigger OfferChangeTrigger  `xml:"OfferChangeTrigger"`
+	Summary            OfferSummary        `xml:"Summary"`
+	Offers             []NotificationOffer `xml:"Offers>Offer"`
+}
+
+type FulfillmentOrderStatusNotification struct {
+	SellerId                 string               `xml:"SellerId"`
+	EventType                string               `xml:"EventType"`
+	StatusUpdateDateTime     time.Time            `xml:"StatusUpdateDateTime"`
+	SellerFulfillmentOrderId string               `xml:"SellerFulfillmentOrderId"`
+	FulfillmentOrderStatus   string               `xml:"FulfillmentOrderStatus"`
+	FulfillmentShipment      *FulfillmentShipment `xml:"FulfillmentShipment"`
+}
+
+type ReportProcessingFinishedNotification struct {
+	SellerId               string `xml:"SellerId"`
+	ReportRequestId        string `xml:"ReportRequestId"`
+	ReportId               string `xml:"ReportId"`
+	ReportType             string `xml:"ReportType"`
+	ReportProcessingStatus string `xml:"ReportProcessingStatus"`
+}
+
+type FeedProcessingFinishedNotification struct {
+	SellerId             string `xml:"SellerId"`
+	FeedSubmissionId     string `xml:"FeedSubmissionId"`
+	FeedType             string `xml:"FeedType"`
+	FeedProcessingStatus string `xml:"FeedProcessingStatus"`
+}
+
+// NotificationPayload holds the decoded payload. Exactly one of the typed
+// fields is set for the supported notification types; Raw always holds the
+// payload XML so that other types can be decoded by the caller.
+type NotificationPayload struct {
+	AnyOfferChanged          *AnyOfferChangedNotification          `xml:"AnyOfferChangedNotification"`
+	FulfillmentOrderStatus   *FulfillmentOrderStatusNotification   `xml:"FulfillmentOrderStatusNotification"`
+	ReportProcessingFinished *ReportProcessingFinishedNotification `xml:"ReportProcessingFinishedNotification"`
+	FeedProcessingFinished   *FeedProcessingFinishedNotification   `xml:"FeedProcessingFinishedNotification"`
+	Raw                      string                                `xml:",innerxml"`
+}
+
+// Notification is an MWS notification message.
+type Notification struct {
+	XMLName              xml.Name             `xml:"Notification"`
+	NotificationMetaData NotificationMetaData `xml:"NotificationMetaData"`
+	NotificationPayload  NotificationPayload  `xml:"NotificationPayload"`
+}
+
+// DecodeNotification decodes the XML of a notification message. It only
+// needs the message body, so it works the same whichever transport delivered
+// the message.
+func DecodeNotification(data []byte) (*Notification, error) {
+	var n Notification
+	if err := xml.Unmarshal(data, &n); err != nil {
+		return nil, err
+	}
+
+	return &n, nil
+}
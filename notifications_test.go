@@ -0,0 +1,92 @@
+package amazonmws
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+const anyOfferChangedMessage = `<?xml version="1.0" encoding="UTF-8"?>
+<Notification>
+  <NotificationMetaData>
+    <NotificationType>AnyOfferChanged</NotificationType>
+    <PayloadVersion>1.0</PayloadVersion>
+    <UniqueId>7d2b5c6f-7dd2-4a4b-8d6f-7e1d1a0b1c2d</UniqueId>
+    <PublishTime>2015-11-30T21:26:37.421Z</PublishTime>
+    <SellerId>A1B2C3D4E5F6G7</SellerId>
+    <MarketplaceId>ATVPDKIKX0DER</MarketplaceId>
+  </NotificationMetaData>
+  <NotificationPayload>
+    <AnyOfferChangedNotification>
+      <OfferChangeTrigger>
+        <MarketplaceId>ATVPDKIKX0DER</MarketplaceId>
+        <ASIN>B00V5FNJ5W</ASIN>
+        <ItemCondition>new</ItemCondition>
+        <TimeOfOfferChange>2015-11-30T21:26:37.298Z</TimeOfOfferChange>
+      </OfferChangeTrigger>
+      <Summary>
+        <NumberOfOffers>
+          <OfferCount condition="new" fulfillmentChannel="Merchant">3</OfferCount>
+        </NumberOfOffers>
+        <LowestPrices>
+          <LowestPrice condition="new" fulfillmentChannel="Merchant">
+            <LandedPrice><Amount>24.99</Amount><CurrencyCode>USD</CurrencyCode></LandedPrice>
+            <ListingPrice><Amount>24.99</Amount><CurrencyCode>USD</CurrencyCode></ListingPrice>
+            <Shipping><Amount>0.00</Amount><CurrencyCode>USD</CurrencyCode></Shipping>
+          </LowestPrice>
+        </LowestPrices>
+      </Summary>
+      <Offers>
+        <Offer>
+          <SellerId>A2ZZZZZZZZZZZZ</SellerId>
+          <SubCondition>new</SubCondition>
+          <ShippingTime minimumHours="24" maximumHours="48" availabilityType="NOW"/>
+          <ListingPrice><Amount>24.99</Amount><CurrencyCode>USD</CurrencyCode></ListingPrice>
+          <Shipping><Amount>0.00</Amount><CurrencyCode>USD</CurrencyCode></Shipping>
+          <IsFulfilledByAmazon>false</IsFulfilledByAmazon>
+          <IsBuyBoxWinner>true</IsBuyBoxWinner>
+        </Offer>
+      </Offers>
+    </AnyOfferChangedNotification>
+  </NotificationPayload>
+</Notification>`
+
+func TestDecodeAnyOfferChangedNotification(t *testing.T) {
+	n, err := DecodeNotification([]byte(anyOfferChangedMessage))
+	assert.Nil(t, err)
+
+	assert.Equal(t, NotificationTypeAnyOfferChanged, n.NotificationMetaData.NotificationType)
+	assert.Equal(t, time.Date(2015, 11, 30, 21, 26, 37, 421000000, time.UTC), n.NotificationMetaData.PublishTime)
+
+	payload := n.NotificationPayload.AnyOfferChanged
+	assert.NotNil(t, payload)
+	assert.Nil(t, n.NotificationPayload.FeedProcessingFinished)
+	assert.Equal(t, "B00V5FNJ5W", payload.OfferChangeTrigger.ASIN)
+	assert.Equal(t, OfferCount{Condition: "new", FulfillmentChannel: "Merchant", Count: 3}, payload.Summary.NumberOfOffers[0])
+	assert.Equal(t, &CurrencyAmount{CurrencyCode: "USD", Amount: "24.99"}, payload.Summary.LowestPrices[0].LandedPrice)
+	assert.Equal(t, 48, payload.Offers[0].ShippingTime.MaximumHours)
+	assert.True(t, payload.Offers[0].IsBuyBoxWinner)
+}
+
+func TestDecodeFeedProcessingFinishedNotification(t *testing.T) {
+	message := `<Notification>
+  <NotificationMetaData><NotificationType>FeedProcessingFinished</NotificationType></NotificationMetaData>
+  <NotificationPayload>
+    <FeedProcessingFinishedNotification>
+      <SellerId>A1B2C3D4E5F6G7</SellerId>
+      <FeedSubmissionId>2020202020</FeedSubmissionId>
+      <FeedType>_POST_PRODUCT_DATA_</FeedType>
+      <FeedProcessingStatus>_DONE_</FeedProcessingStatus>
+    </FeedProcessingFinishedNotification>
+  </NotificationPayload>
+</Notification>`
+
+	n, err := DecodeNotification([]byte(message))
+	assert.Nil(t, err)
+	assert.Equal(t, &FeedProcessingFinishedNotification{
+		SellerId:             "A1B2C3D4E5F6G7",
+		FeedSubmissionId:     "2020202020",
+		FeedType:             "_POST_PRODUCT_DATA_",
+		FeedProcessingStatus: "_DONE_",
+	}, n.NotificationPayload.FeedProcessingFinished)
+}
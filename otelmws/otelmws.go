@@ -0,0 +1,65 @@
+// Package otelmws adapts an OpenTelemetry tracer to amazonmws.Tracer:
+//
+//	api.Tracer = otelmws.NewTracer(otel.Tracer("amazonmws"))
+package otelmws
+
+import (
+	"context"
+	"fmt"
+
+	amazonmws "github.com/ecommelite/go-amazon-mws-api"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracer starts OpenTelemetry spans for amazonmws. The HTTP round trip is a
+// client span; every other span is internal.
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+func NewTracer(tracer trace.Tracer) Tracer {
+	return Tracer{tracer: tracer}
+}
+
+func (t Tracer) Start(ctx context.Context, name string) (context.Context, amazonmws.Span) {
+	kind := trace.SpanKindInternal
+	if name == "mws.http" {
+		kind = trace.SpanKindClient
+	}
+
+	ctx, span := t.tracer.Start(ctx, name, trace.WithSpanKind(kind))
+	return ctx, Span{span: span}
+}
+
+// Span wraps an OpenTelemetry span. Errors also set the span status.
+type Span struct {
+	span trace.Span
+}
+
+func (s Span) SetAttribute(key string, value interface{}) {
+	switch v := value.(type) {
+	case string:
+		s.span.SetAttributes(attribute.String(key, v))
+	case int:
+		s.span.SetAttributes(attribute.Int(key, v))
+	case int64:
+		s.span.SetAttributes(attribute.Int64(key, v))
+	case float64:
+		s.span.SetAttributes(attribute.Float64(key, v))
+	case bool:
+		s.span.SetAttributes(attribute.Bool(key, v))
+	default:
+		s.span.SetAttributes(attribute.String(key, fmt.Sprint(v)))
+	}
+}
+
+func (s Span) RecordError(err error) {
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+func (s Span) End() {
+	s.span.End()
+}
@@ -0,0 +1,59 @@
+package otelmws
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	tracer := NewTracer(provider.Tracer("amazonmws"))
+
+	ctx, root := tracer.Start(context.Background(), "MWS ListOrders")
+	root.SetAttribute("mws.action", "ListOrders")
+	root.SetAttribute("http.status_code", 503)
+	root.SetAttribute("mws.retry_attempt", 1)
+	_, child := tracer.Start(ctx, "mws.http")
+	child.RecordError(errors.New("connection reset"))
+	child.End()
+	root.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	httpSpan, rootSpan := spans[0], spans[1]
+
+	if httpSpan.Parent().SpanID() != rootSpan.SpanContext().SpanID() {
+		t.Errorf("mws.http is not a child of the request span")
+	}
+	if httpSpan.SpanKind() != trace.SpanKindClient || rootSpan.SpanKind() != trace.SpanKindInternal {
+		t.Errorf("got kinds %v and %v", httpSpan.SpanKind(), rootSpan.SpanKind())
+	}
+	if httpSpan.Status().Code != codes.Error || httpSpan.Status().Description != "connection reset" {
+		t.Errorf("got status %+v", httpSpan.Status())
+	}
+
+	want := []attribute.KeyValue{
+		attribute.String("mws.action", "ListOrders"),
+		attribute.Int("http.status_code", 503),
+		attribute.Int("mws.retry_attempt", 1),
+	}
+	got := rootSpan.Attributes()
+	if len(got) != len(want) {
+		t.Fatalf("got attributes %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("attribute %d: got %v, want %v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,462 @@
+package amazonmws
+
+import (
+	"strconv"
+	"time"
+)
+
+// ShippingSpeedCategory is the delivery speed of a fulfillment order.
+type ShippingSpeedCategory string
+
+const (
+	ShippingSpeedStandard          ShippingSpeedCategory = "Standard"
+	ShippingSpeedExpedited         ShippingSpeedCategory = "Expedited"
+	ShippingSpeedPriority          ShippingSpeedCategory = "Priority"
+	ShippingSpeedScheduledDelivery ShippingSpeedCategory = "ScheduledDelivery"
+)
+
+// FulfillmentAddress is the destination address of a fulfillment order.
+type FulfillmentAddress struct {
+	Name                string `xml:"Name"`
+	Line1               string `xml:"Line1"`
+	Line2               string `xml:"Line2"`
+	Line3               string `xml:"Line3"`
+	DistrictOrCounty    string `xml:"DistrictOrCounty"`
+	City                string `xml:"City"`
+	StateOrProvinceCode string `xml:"StateOrProvinceCode"`
+	CountryCode         string `xml:"CountryCode"`
+	PostalCode          string `xml:"PostalCode"`
+	PhoneNumber         string `xml:"PhoneNumber"`
+}
+
+func (a FulfillmentAddress) appendQuery(params map[string]string, prefix string) {
+	params[prefix+".Name"] = a.Name
+	params[prefix+".Line1"] = a.Line1
+	if a.Line2 != "" {
+		params[prefix+".Line2"] = a.Line2
+	}
+	if a.Line3 != "" {
+		params[prefix+".Line3"] = a.Line3
+	}
+	if a.DistrictOrCounty != "" {
+		params[prefix+".DistrictOrCounty"] = a.DistrictOrCounty
+	}
+	if a.City != "" {
+		params[prefix+".City"] = a.City
+	}
+	params[prefix+".StateOrProvinceCode"] = a.StateOrProvinceCode
+	params[prefix+".CountryCode"] = a.CountryCode
+	if a.PostalCode != "" {
+		params[prefix+".PostalCode"] = a.PostalCode
+	}
+	if a.PhoneNumber != "" {
+		params[prefix+".PhoneNumber"] = a.PhoneNumber
+	}
+}
+
+func (a Amount) appendQuery(params map[string]string, prefix string) {
+	params[prefix+".CurrencyCode"] = a.CurrencyCode
+	params[prefix+".Value"] = a.Value
+}
+
+// FulfillmentPreviewItem is an item of a GetFulfillmentPreview request.
+type FulfillmentPreviewItem struct {
+	SellerSKU                    string
+	SellerFulfillmentOrderItemId string
+	Quantity                     int
+}
+
+func (item FulfillmentPreviewItem) appendQuery(params map[string]string, index int) {
+	prefix := "Items.member." + strconv.Itoa(index+1)
+
+	params[prefix+".SellerSKU"] = item.SellerSKU
+	params[prefix+".SellerFulfillmentOrderItemId"] = item.SellerFulfillmentOrderItemId
+	params[prefix+".Quantity"] = strconv.Itoa(item.Quantity)
+}
+
+// CreateFulfillmentOrderItem is an item of a CreateFulfillmentOrder or
+// UpdateFulfillmentOrder request.
+type CreateFulfillmentOrderItem struct {
+	SellerSKU                    string
+	SellerFulfillmentOrderItemId string
+	Quantity                     int
+	GiftMessage                  string
+	DisplayableComment           string
+	FulfillmentNetworkSKU        string
+	PerUnitDeclaredValue         *Amount
+	PerUnitPrice                 *Amount
+	PerUnitTax                   *Amount
+}
+
+func (item CreateFulfillmentOrderItem) appendQuery(params map[string]string, index int) {
+	prefix := "Items.member." + strconv.Itoa(index+1)
+
+	params[prefix+".SellerSKU"] = item.SellerSKU
+	params[prefix+".SellerFulfillmentOrderItemId"] = item.SellerFulfillmentOrderItemId
+	params[prefix+".Quantity"] = strconv.Itoa(item.Quantity)
+	if item.GiftMessage != "" {
+		params[prefix+".GiftMessage"] = item.GiftMessage
+	}
+	if item.DisplayableComment != "" {
+		params[prefix+".DisplayableComment"] = item.DisplayableComment
+	}
+	if item.FulfillmentNetworkSKU != "" {
+		params[prefix+".FulfillmentNetworkSKU"] = item.FulfillmentNetworkSKU
+	}
+	if item.PerUnitDeclaredValue != nil {
+		item.PerUnitDeclaredValue.appendQuery(params, prefix+".PerUnitDeclaredValue")
+	}
+	if item.PerUnitPrice != nil {
+		item.PerUnitPrice.appendQuery(params, prefix+".PerUnitPrice")
+	}
+	if item.PerUnitTax != nil {
+		item.PerUnitTax.appendQuery(params, prefix+".PerUnitTax")
+	}
+}
+
+type GetFulfillmentPreviewRequest struct {
+	MarketplaceId                *string
+	Address                      FulfillmentAddress
+	Items                        []FulfillmentPreviewItem
+	ShippingSpeedCategories      []ShippingSpeedCategory
+	IncludeCODFulfillmentPreview bool
+	IncludeDeliveryWindows       bool
+}
+
+type FulfillmentPreviewFee struct {
+	Name   string `xml:"Name"`
+	Amount Amount `xml:"Amount"`
+}
+
+type FulfillmentPreviewShipmentItem struct {
+	SellerSKU                       string `xml:"SellerSKU"`
+	SellerFulfillmentOrderItemId    string `xml:"SellerFulfillmentOrderItemId"`
+	Quantity                        int    `xml:"Quantity"`
+	EstimatedShippingWeight         Weight `xml:"EstimatedShippingWeight"`
+	ShippingWeightCalculationMethod string `xml:"ShippingWeightCalculationMethod"`
+}
+
+type FulfillmentPreviewShipment struct {
+	EarliestShipDate        time.Time                        `xml:"EarliestShipDate"`
+	LatestShipDate          time.Time                        `xml:"LatestShipDate"`
+	EarliestArrivalDate     time.Time                        `xml:"EarliestArrivalDate"`
+	LatestArrivalDate       time.Time                        `xml:"LatestArrivalDate"`
+	ShippingNotes           []string                         `xml:"ShippingNotes>member"`
+	FulfillmentPreviewItems []FulfillmentPreviewShipmentItem `xml:"FulfillmentPreviewItems>member"`
+}
+
+type UnfulfillablePreviewItem struct {
+	SellerSKU                    string   `xml:"SellerSKU"`
+	SellerFulfillmentOrderItemId string   `xml:"SellerFulfillmentOrderItemId"`
+	Quantity                     int      `xml:"Quantity"`
+	ItemUnfulfillableReasons     []string `xml:"ItemUnfulfillableReasons>member"`
+}
+
+type FulfillmentPreview struct {
+	ShippingSpeedCategory       ShippingSpeedCategory        `xml:"ShippingSpeedCategory"`
+	IsFulfillable               bool                         `xml:"IsFulfillable"`
+	IsCODCapable                bool                         `xml:"IsCODCapable"`
+	MarketplaceId               string                       `xml:"MarketplaceId"`
+	EstimatedShippingWeight     Weight                       `xml:"EstimatedShippingWeight"`
+	EstimatedFees               []FulfillmentPreviewFee      `xml:"EstimatedFees>member"`
+	FulfillmentPreviewShipments []FulfillmentPreviewShipment `xml:"FulfillmentPreviewShipments>member"`
+	UnfulfillablePreviewItems   []UnfulfillablePreviewItem   `xml:"UnfulfillablePreviewItems>member"`
+	OrderUnfulfillableReasons   []string                     `xml:"OrderUnfulfillableReasons>member"`
+}
+
+type getFulfillmentPreviewResponse struct {
+	FulfillmentPreviews []FulfillmentPreview `xml:"GetFulfillmentPreviewResult>FulfillmentPreviews>member"`
+	ResponseMetadata    ResponseMetadata     `xml:"ResponseMetadata"`
+}
+
+func (api AmazonMWSAPI) GetFulfillmentPreview(req GetFulfillmentPreviewRequest) ([]FulfillmentPreview, Quota, error) {
+	params := make(map[string]string)
+
+	if req.MarketplaceId != nil {
+		params["MarketplaceId"] = *req.MarketplaceId
+	} else {
+		params["MarketplaceId"] = api.MarketplaceId
+	}
+	req.Address.appendQuery(params, "Address")
+	for i, item := range req.Items {
+		item.appendQuery(params, i)
+	}
+	for i, speed := range req.ShippingSpeedCategories {
+		params["ShippingSpeedCategories.member."+strconv.Itoa(i+1)] = string(speed)
+	}
+	if req.IncludeCODFulfillmentPreview {
+		params["IncludeCODFulfillmentPreview"] = "true"
+	}
+	if req.IncludeDeliveryWindows {
+		params["IncludeDeliveryWindows"] = "true"
+	}
+
+	var resp getFulfillmentPreviewResponse
+	quota, err := api.fetchAndDecode("GetFulfillmentPreview", "/FulfillmentOutboundShipment/2010-10-01", params, nil, &resp)
+
+	return resp.FulfillmentPreviews, quota, err
+}
+
+type CreateFulfillmentOrderRequest struct {
+	MarketplaceId            *string
+	SellerFulfillmentOrderId string
+	FulfillmentAction        string
+	DisplayableOrderId       string
+	DisplayableOrderDateTime time.Time
+	DisplayableOrderComment  string
+	ShippingSpeedCategory    ShippingSpeedCategory
+	DestinationAddress       FulfillmentAddress
+	FulfillmentPolicy        string
+	NotificationEmailList    []string
+	Items                    []CreateFulfillmentOrderItem
+}
+
+func (api AmazonMWSAPI) CreateFulfillmentOrder(req CreateFulfillmentOrderRequest) (Quota, error) {
+	params := make(map[string]string)
+
+	if req.MarketplaceId != nil {
+		params["MarketplaceId"] = *req.MarketplaceId
+	} else {
+		params["MarketplaceId"] = api.MarketplaceId
+	}
+	params["SellerFulfillmentOrderId"] = req.SellerFulfillmentOrderId
+	if req.FulfillmentAction != "" {
+		params["FulfillmentAction"] = req.FulfillmentAction
+	}
+	params["DisplayableOrderId"] = req.DisplayableOrderId
+	params["DisplayableOrderDateTime"] = formatTime(req.DisplayableOrderDateTime)
+	params["DisplayableOrderComment"] = req.DisplayableOrderComment
+	params["ShippingSpeedCategory"] = string(req.ShippingSpeedCategory)
+	req.DestinationAddress.appendQuery(params, "DestinationAddress")
+	if req.FulfillmentPolicy != "" {
+		params["FulfillmentPolicy"] = req.FulfillmentPolicy
+	}
+	for i, email := range req.NotificationEmailList {
+		params["NotificationEmailList.member."+strconv.Itoa(i+1)] = email
+	}
+	for i, item := range req.Items {
+		item.appendQuery(params, i)
+	}
+
+	return api.fetchAndDecode("CreateFulfillmentOrder", "/FulfillmentOutboundShipment/2010-10-01", params, nil, nil)
+}
+
+// UpdateFulfillmentOrderRequest changes an order that has not shipped yet.
+// Only the fields that are set are sent to Amazon.
+type UpdateFulfillmentOrderRequest struct {
+	MarketplaceId            *string
+	SellerFulfillmentOrderId string
+	FulfillmentAction        string
+	DisplayableOrderId       string
+	DisplayableOrderDateTime *time.Time
+	DisplayableOrderComment  string
+	ShippingSpeedCategory    ShippingSpeedCategory
+	DestinationAddress       *FulfillmentAddress
+	FulfillmentPolicy        string
+	NotificationEmailList    []string
+	Items                    []CreateFulfillmentOrderItem
+}
+
+func (api AmazonMWSAPI) UpdateFulfillmentOrder(req UpdateFulfillmentOrderRequest) (Quota, error) {
+	params := make(map[string]string)
+
+	if req.MarketplaceId != nil {
+		params["MarketplaceId"] = *req.MarketplaceId
+	}
+	params["SellerFulfillmentOrderId"] = req.SellerFulfillmentOrderId
+	if req.FulfillmentAction != "" {
+		params["FulfillmentAction"] = req.FulfillmentAction
+	}
+	if req.DisplayableOrderId != "" {
+		params["DisplayableOrderId"] = req.DisplayableOrderId
+	}
+	if req.DisplayableOrderDateTime != nil {
+		params["DisplayableOrderDateTime"] = formatTime(*req.DisplayableOrderDateTime)
+	}
+	if req.DisplayableOrderComment != "" {
+		params["DisplayableOrderComment"] = req.DisplayableOrderComment
+	}
+	if req.ShippingSpeedCategory != "" {
+		params["ShippingSpeedCategory"] = string(req.ShippingSpeedCategory)
+	}
+	if req.DestinationAddress != nil {
+		req.DestinationAddress.appendQuery(params, "DestinationAddress")
+	}
+	if req.FulfillmentPolicy != "" {
+		params["FulfillmentPolicy"] = req.FulfillmentPolicy
+	}
+	for i, email := range req.NotificationEmailList {
+		params["NotificationEmailList.member."+strconv.Itoa(i+1)] = email
+	}
+	for i, item := range req.Items {
+		item.appendQuery(params, i)
+	}
+
+	return api.fetchAndDecode("UpdateFulfillmentOrder", "/FulfillmentOutboundShipment/2010-10-01", params, nil, nil)
+}
+
+type FulfillmentOrder struct {
+	SellerFulfillmentOrderId string                `xml:"SellerFulfillmentOrderId"`
+	MarketplaceId            string                `xml:"MarketplaceId"`
+	DisplayableOrderId       string                `xml:"DisplayableOrderId"`
+	DisplayableOrderDateTime time.Time             `xml:"DisplayableOrderDateTime"`
+	DisplayableOrderComment  string                `xml:"DisplayableOrderComment"`
+	ShippingSpeedCategory    ShippingSpeedCategory `xml:"ShippingSpeedCategory"`
+	DestinationAddress       FulfillmentAddress    `xml:"DestinationAddress"`
+	FulfillmentAction        string                `xml:"FulfillmentAction"`
+	FulfillmentPolicy        string                `xml:"FulfillmentPolicy"`
+	ReceivedDateTime         time.Time             `xml:"ReceivedDateTime"`
+	FulfillmentOrderStatus   string                `xml:"FulfillmentOrderStatus"`
+	StatusUpdatedDateTime    time.Time             `xml:"StatusUpdatedDateTime"`
+	NotificationEmailList    []string              `xml:"NotificationEmailList>member"`
+}
+
+type FulfillmentOrderItem struct {
+	SellerSKU                    string    `xml:"SellerSKU"`
+	SellerFulfillmentOrderItemId string    `xml:"SellerFulfillmentOrderItemId"`
+	Quantity                     int       `xml:"Quantity"`
+	GiftMessage                  string    `xml:"GiftMessage"`
+	DisplayableComment           string    `xml:"DisplayableComment"`
+	FulfillmentNetworkSKU        string    `xml:"FulfillmentNetworkSKU"`
+	OrderItemDisposition         string    `xml:"OrderItemDisposition"`
+	CancelledQuantity            int       `xml:"CancelledQuantity"`
+	UnfulfillableQuantity        int       `xml:"UnfulfillableQuantity"`
+	EstimatedShipDateTime        time.Time `xml:"EstimatedShipDateTime"`
+	EstimatedArrivalDateTime     time.Time `xml:"EstimatedArrivalDateTime"`
+	PerUnitDeclaredValue         *Amount   `xml:"PerUnitDeclaredValue"`
+}
+
+type FulfillmentShipmentItem struct {
+	SellerSKU                    string `xml:"SellerSKU"`
+	SellerFulfillmentOrderItemId string `xml:"SellerFulfillmentOrderItemId"`
+	Quantity                     int    `xml:"Quantity"`
+	PackageNumber                int    `xml:"PackageNumber"`
+}
+
+type FulfillmentShipmentPackage struct {
+	PackageNumber            int       `xml:"PackageNumber"`
+	CarrierCode              string    `xml:"CarrierCode"`
+	TrackingNumber           string    `xml:"TrackingNumber"`
+	EstimatedArrivalDateTime time.Time `xml:"EstimatedArrivalDateTime"`
+}
+
+type FulfillmentShipment struct {
+	AmazonShipmentId           string                       `xml:"AmazonShipmentId"`
+	FulfillmentCenterId        string                       `xml:"FulfillmentCenterId"`
+	FulfillmentShipmentStatus  string                       `xml:"FulfillmentShipmentStatus"`
+	ShippingDateTime           time.Time                    `xml:"ShippingDateTime"`
+	EstimatedArrivalDateTime   time.Time                    `xml:"EstimatedArrivalDateTime"`
+	FulfillmentShipmentItem    []FulfillmentShipmentItem    `xml:"FulfillmentShipmentItem>member"`
+	FulfillmentShipmentPackage []FulfillmentShipmentPackage `xml:"FulfillmentShipmentPackage>member"`
+}
+
+type GetFulfillmentOrderResult struct {
+	FulfillmentOrder     FulfillmentOrder       `xml:"FulfillmentOrder"`
+	FulfillmentOrderItem []FulfillmentOrderItem `xml:"FulfillmentOrderItem>member"`
+	FulfillmentShipment  []FulfillmentShipment  `xml:"FulfillmentShipment>member"`
+}
+
+type getFulfillmentOrderResponse struct {
+	Result           GetFulfillmentOrderResult `xml:"GetFulfillmentOrderResult"`
+	ResponseMetadata ResponseMetadata          `xml:"ResponseMetadata"`
+}
+
+func (api AmazonMWSAPI) GetFulfillmentOrder(sellerFulfillmentOrderId string) (GetFulfillmentOrderResult, Quota, error) {
+	params := make(map[string]string)
+	params["SellerFulfillmentOrderId"] = sellerFulfillmentOrderId
+
+	var resp getFulfillmentOrderResponse
+	quota, err := api.fetchAndDecode("GetFulfillmentOrder", "/FulfillmentOutboundShipment/2010-10-01", params, nil, &resp)
+
+	return resp.Result, quota, err
+}
+
+type ListAllFulfillmentOrdersResult struct {
+	NextToken         string             `xml:"NextToken"`
+	FulfillmentOrders []FulfillmentOrder `xml:"FulfillmentOrders>member"`
+}
+
+type listAllFulfillmentOrdersResponse struct {
+	Result           ListAllFulfillmentOrdersResult `xml:"ListAllFulfillmentOrdersResult"`
+	ResponseMetadata ResponseMetadata               `xml:"ResponseMetadata"`
+}
+
+type listAllFulfillmentOrdersByNextTokenResponse struct {
+	Result           ListAllFulfillmentOrdersResult `xml:"ListAllFulfillmentOrdersByNextTokenResult"`
+	ResponseMetadata ResponseMetadata               `xml:"ResponseMetadata"`
+}
+
+// ListAllFulfillmentOrders returns the orders updated after queryStartDateTime,
+// or those of the last 36 hours when it is nil.
+func (api AmazonMWSAPI) ListAllFulfillmentOrders(queryStartDateTime *time.Time) (ListAllFulfillmentOrdersResult, Quota, error) {
+	params := make(map[string]string)
+	if queryStartDateTime != nil {
+		params["QueryStartDateTime"] = formatTime(*queryStartDateTime)
+	}
+
+	var resp listAllFulfillmentOrdersResponse
+	quota, err := api.fetchAndDecode("ListAllFulfillmentOrders", "/FulfillmentOutboundShipment/2010-10-01", params, nil, &resp)
+
+	return resp.Result, quota, err
+}
+
+func (api AmazonMWSAPI) ListAllFulfillmentOrdersByNextToken(nextToken string) (ListAllFulfillmentOrdersResult, Quota, error) {
+	params := make(map[string]string)
+	params["NextToken"] = nextToken
+
+	var resp listAllFulfillmentOrdersByNextTokenResponse
+	quota, err := api.fetchAndDecode("ListAllFulfillmentOrdersByNextToken", "/FulfillmentOutboundShipment/2010-10-01", params, nil, &resp)
+
+	return resp.Result, quota, err
+}
+
+func (api AmazonMWSAPI) CancelFulfillmentOrder(sellerFulfillmentOrderId string) (Quota, error) {
+	params := make(map[string]string)
+	params["SellerFulfillmentOrderId"] = sellerFulfillmentOrderId
+
+	return api.fetchAndDecode("CancelFulfillmentOrder", "/FulfillmentOutboundShipment/2010-10-01", params, nil, nil)
+}
+
+type TrackingAddress struct {
+	City    string `xml:"City"`
+	State   string `xml:"State"`
+	Country string `xml:"Country"`
+}
+
+type TrackingEvent struct {
+	EventDate    time.Time       `xml:"EventDate"`
+	EventAddress TrackingAddress `xml:"EventAddress"`
+	EventCode    string          `xml:"EventCode"`
+}
+
+type PackageTrackingDetails struct {
+	PackageNumber          int             `xml:"PackageNumber"`
+	TrackingNumber         string          `xml:"TrackingNumber"`
+	CarrierCode            string          `xml:"CarrierCode"`
+	CarrierPhoneNumber     string          `xml:"CarrierPhoneNumber"`
+	CarrierURL             string          `xml:"CarrierURL"`
+	ShipDate               time.Time       `xml:"ShipDate"`
+	EstimatedArrivalDate   time.Time       `xml:"EstimatedArrivalDate"`
+	ShipToAddress          TrackingAddress `xml:"ShipToAddress"`
+	CurrentStatus          string          `xml:"CurrentStatus"`
+	SignedForBy            string          `xml:"SignedForBy"`
+	AdditionalLocationInfo string          `xml:"AdditionalLocationInfo"`
+	TrackingEvents         []TrackingEvent `xml:"TrackingEvents>member"`
+}
+
+type getPackageTrackingDetailsResponse struct {
+	Result           PackageTrackingDetails `xml:"GetPackageTrackingDetailsResult"`
+	ResponseMetadata ResponseMetadata       `xml:"ResponseMetadata"`
+}
+
+// GetPackageTrackingDetails returns tracking information for a package number
+// taken from a FulfillmentShipmentPackage.
+func (api AmazonMWSAPI) GetPackageTrackingDetails(packageNumber int) (PackageTrackingDetails, Quota, error) {
+	params := make(map[string]string)
+	params["PackageNumber"] = strconv.Itoa(packageNumber)
+
+	var resp getPackageTrackingDetailsResponse
+	quota, err := api.fetchAndDecode("GetPackageTrackingDetails", "/FulfillmentOutboundShipment/2010-10-01", params, nil, &resp)
+
+	return resp.Result, quota, err
+}
@@ -0,0 +1,63 @@
+package amazonmws
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCreateFulfillmentOrderItemQuery(t *testing.T) {
+	params := make(map[string]string)
+
+	items := []CreateFulfillmentOrderItem{
+		{SellerSKU: "SKU-1", SellerFulfillmentOrderItemId: "1", Quantity: 2},
+		{SellerSKU: "SKU-2", SellerFulfillmentOrderItemId: "2", Quantity: 1, PerUnitDeclaredValue: &Amount{CurrencyCode: "USD", Value: "19.99"}},
+	}
+	for i, item := range items {
+		item.appendQuery(params, i)
+	}
+	FulfillmentAddress{Name: "Jane Doe", Line1: "1 Main St", City: "Seattle", StateOrProvinceCode: "WA", CountryCode: "US", PostalCode: "98101"}.appendQuery(params, "DestinationAddress")
+
+	assert.Equal(t, "SKU-1", params["Items.member.1.SellerSKU"])
+	assert.Equal(t, "2", params["Items.member.1.Quantity"])
+	assert.Equal(t, "USD", params["Items.member.2.PerUnitDeclaredValue.CurrencyCode"])
+	assert.Equal(t, "19.99", params["Items.member.2.PerUnitDeclaredValue.Value"])
+	assert.Equal(t, "Jane Doe", params["DestinationAddress.Name"])
+	assert.Equal(t, "WA", params["DestinationAddress.StateOrProvinceCode"])
+}
+
+func TestDecodeGetFulfillmentPreview(t *testing.T) {
+	body := `<GetFulfillmentPreviewResponse xmlns="http://mws.amazonaws.com/FulfillmentOutboundShipment/2010-10-01/">
+  <GetFulfillmentPreviewResult>
+    <FulfillmentPreviews>
+      <member>
+        <ShippingSpeedCategory>Expedited</ShippingSpeedCategory>
+        <IsFulfillable>true</IsFulfillable>
+        <EstimatedShippingWeight><Unit>POUNDS</Unit><Value>2</Value></EstimatedShippingWeight>
+        <EstimatedFees>
+          <member><Name>FBAPerUnitFulfillmentFee</Name><Amount><CurrencyCode>USD</CurrencyCode><Value>3.99</Value></Amount></member>
+        </EstimatedFees>
+        <FulfillmentPreviewShipments>
+          <member>
+            <LatestShipDate>2010-11-15T06:59:59Z</LatestShipDate>
+            <FulfillmentPreviewItems>
+              <member><SellerSKU>SKU-1</SellerSKU><Quantity>2</Quantity><SellerFulfillmentOrderItemId>1</SellerFulfillmentOrderItemId></member>
+            </FulfillmentPreviewItems>
+          </member>
+        </FulfillmentPreviewShipments>
+      </member>
+    </FulfillmentPreviews>
+  </GetFulfillmentPreviewResult>
+  <ResponseMetadata><RequestId>7b0a4b3e-1d48-4b14-b6c4-4e27f9e4a1b8</RequestId></ResponseMetadata>
+</GetFulfillmentPreviewResponse>`
+
+	var resp getFulfillmentPreviewResponse
+	err := decodeResponse(body, &resp)
+	assert.Nil(t, err)
+
+	preview := resp.FulfillmentPreviews[0]
+	assert.Equal(t, ShippingSpeedExpedited, preview.ShippingSpeedCategory)
+	assert.True(t, preview.IsFulfillable)
+	assert.Equal(t, Weight{Unit: "POUNDS", Value: "2"}, preview.EstimatedShippingWeight)
+	assert.Equal(t, Amount{CurrencyCode: "USD", Value: "3.99"}, preview.EstimatedFees[0].Amount)
+	assert.Equal(t, "SKU-1", preview.FulfillmentPreviewShipments[0].FulfillmentPreviewItems[0].SellerSKU)
+}
@@ -0,0 +1,72 @@
+package amazonmws
+
+import (
+	"net/url"
+	"strings"
+	"time"
+)
+
+// PresignURL returns a signed GET URL for action in the API section at the
+// given path, such as "/Products/2011-10-01", that can be used without the
+// secret key, for example from curl or a browser. params holds the
+// operation's own parameters and is not modified.
+//
+// When expiresIn is positive the URL carries an Expires parameter and stays
+// valid until then; otherwise it carries a Timestamp, which Amazon accepts
+// for 15 minutes.
+func (api AmazonMWSAPI) PresignURL(action, section string, params map[string]string, expiresIn time.Duration) (string, error) {
+	version, ok := sectionVersion(section)
+	if !ok {
+		return "", unknownSectionError(section)
+	}
+
+	u, err := presignBase(api.Host, section)
+	if err != nil {
+		return "", err
+	}
+
+	creds, err := api.credentials()
+	if err != nil {
+		return "", err
+	}
+
+	query := make(map[string]string, len(params))
+	for k, v := range params {
+		query[k] = v
+	}
+	setAuthParams(query, creds, action, version)
+	query["SellerId"] = api.SellerId
+
+	now := time.Now().UTC()
+	if expiresIn > 0 {
+		delete(query, "Timestamp")
+		query["Expires"] = now.Add(expiresIn).Format(time.RFC3339)
+	} else {
+		delete(query, "Expires")
+		query["Timestamp"] = now.Format(time.RFC3339)
+	}
+
+	u.RawQuery, err = Signer{SecretKey: creds.SecretKey}.SignQuery("GET", u.Host, u.Path, query)
+	if err != nil {
+		return "", err
+	}
+
+	return u.String(), nil
+}
+
+// presignBase returns the URL of section on host, which may be given with or
+// without a scheme, such as "mws.amazonservices.com" or
+// "https://mws.amazonservices.com".
+func presignBase(host, section string) (*url.URL, error) {
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, err
+	}
+	u.Scheme = "https"
+	u.Path = section
+
+	return u, nil
+}
@@ -0,0 +1,212 @@
+package amazonmws
+
+import (
+	"strconv"
+	"time"
+)
+
+const (
+	RecommendationCategoryInventory      = "Inventory"
+	RecommendationCategorySelection      = "Selection"
+	RecommendationCategoryPricing        = "Pricing"
+	RecommendationCategoryFulfillment    = "Fulfillment"
+	RecommendationCategoryListingQuality = "ListingQuality"
+	RecommendationCategoryGlobalSelling  = "GlobalSelling"
+	RecommendationCategoryAdvertising    = "Advertising"
+)
+
+type RecommendationsLastUpdated struct {
+	InventoryRecommendationsLastUpdated      time.Time `xml:"InventoryRecommendationsLastUpdated"`
+	SelectionRecommendationsLastUpdated      time.Time `xml:"SelectionRecommendationsLastUpdated"`
+	PricingRecommendationsLastUpdated        time.Time `xml:"PricingRecommendationsLastUpdated"`
+	FulfillmentRecommendationsLastUpdated    time.Time `xml:"FulfillmentRecommendationsLastUpdated"`
+	ListingQualityRecommendationsLastUpdated time.Time `xml:"ListingQualityRecommendationsLastUpdated"`
+	GlobalSellingRecommendationsLastUpdated  time.Time `xml:"GlobalSellingRecommendationsLastUpdated"`
+	AdvertisingRecommendationsLastUpdated    time.Time `xml:"AdvertisingRecommendationsLastUpdated"`
+}
+
+type getLastUpdatedTimeForRecommendationsResponse struct {
+	Result           RecommendationsLastUpdated `xml:"GetLastUpdatedTimeForRecommendationsResult"`
+	ResponseMetadata ResponseMetadata           `xml:"ResponseMetadata"`
+}
+
+func (api AmazonMWSAPI) GetLastUpdatedTimeForRecommendations() (RecommendationsLastUpdated, Quota, error) {
+	params := make(map[string]string)
+	params["MarketplaceId"] = api.MarketplaceId
+
+	var resp getLastUpdatedTimeForRecommendationsResponse
+	quota, err := api.fetchAndDecode("GetLastUpdatedTimeForRecommendations", "/Recommendations/2013-04-01", params, nil, &resp)
+
+	return resp.Result, quota, err
+}
+
+type ItemIdentifier struct {
+	Asin string `xml:"Asin"`
+	Sku  string `xml:"Sku"`
+	UPC  string `xml:"UPC"`
+}
+
+// RecommendationHeader holds the fields every recommendation type shares.
+type RecommendationHeader struct {
+	RecommendationId     string         `xml:"RecommendationId"`
+	RecommendationReason string         `xml:"RecommendationReason"`
+	LastUpdated          time.Time      `xml:"LastUpdated"`
+	ItemIdentifier       ItemIdentifier `xml:"ItemIdentifier"`
+	ItemName             string         `xml:"ItemName"`
+}
+
+type RecommendationDimension struct {
+	Value string `xml:"Value"`
+	Unit  string `xml:"Unit"`
+}
+
+type ItemDimensions struct {
+	Height RecommendationDimension `xml:"Height"`
+	Width  RecommendationDimension `xml:"Width"`
+	Length RecommendationDimension `xml:"Length"`
+	Weight RecommendationDimension `xml:"Weight"`
+}
+
+type InventoryRecommendation struct {
+	RecommendationHeader
+	FulfillmentChannel         string `xml:"FulfillmentChannel"`
+	SalesForTheLast14Days      int    `xml:"SalesForTheLast14Days"`
+	SalesForTheLast30Days      int    `xml:"SalesForTheLast30Days"`
+	AvailableQuantity          int    `xml:"AvailableQuantity"`
+	DaysUntilStockRunsOut      int    `xml:"DaysUntilStockRunsOut"`
+	InboundQuantity            int    `xml:"InboundQuantity"`
+	RecommendedInboundQuantity int    `xml:"RecommendedInboundQuantity"`
+	DaysOutOfStockLast30Days   int    `xml:"DaysOutOfStockLast30Days"`
+	LostSalesInLast30Days      int    `xml:"LostSalesInLast30Days"`
+}
+
+// ProductRecommendation holds the catalog details shared by the selection,
+// fulfillment and global selling recommendations.
+type ProductRecommendation struct {
+	BrandName                       string          `xml:"BrandName"`
+	ProductCategory                 string          `xml:"ProductCategory"`
+	SalesRank                       int             `xml:"SalesRank"`
+	BuyboxPrice                     *CurrencyAmount `xml:"BuyboxPrice"`
+	NumberOfOffers                  int             `xml:"NumberOfOffers"`
+	NumberOfOffersFulfilledByAmazon int             `xml:"NumberOfOffersFulfilledByAmazon"`
+	AverageCustomerReview           float64         `xml:"AverageCustomerReview"`
+	NumberOfCustomerReviews         int             `xml:"NumberOfCustomerReviews"`
+}
+
+type SelectionRecommendation struct {
+	RecommendationHeader
+	ProductRecommendation
+}
+
+type PricingRecommendation struct {
+	RecommendationHeader
+	Condition                         string          `xml:"Condition"`
+	SubCondition                      string          `xml:"SubCondition"`
+	FulfillmentChannel                string          `xml:"FulfillmentChannel"`
+	NumberOfOffers                    int             `xml:"NumberOfOffers"`
+	NumberOfMatchingOffers            int             `xml:"NumberOfMatchingOffers"`
+	YourPricePlusShipping             *CurrencyAmount `xml:"YourPricePlusShipping"`
+	LowestPricePlusShipping           *CurrencyAmount `xml:"LowestPricePlusShipping"`
+	PriceDifferenceToLowPrice         *CurrencyAmount `xml:"PriceDifferenceToLowPrice"`
+	MedianPricePlusShipping           *CurrencyAmount `xml:"MedianPricePlusShipping"`
+	LowestMerchantFulfilledOfferPrice *CurrencyAmount `xml:"LowestMerchantFulfilledOfferPrice"`
+	LowestAmazonFulfilledOfferPrice   *CurrencyAmount `xml:"LowestAmazonFulfilledOfferPrice"`
+	NumberOfMerchantFulfilledOffers   int             `xml:"NumberOfMerchantFulfilledOffers"`
+	NumberOfAmazonFulfilledOffers     int             `xml:"NumberOfAmazonFulfilledOffers"`
+}
+
+type FulfillmentRecommendation struct {
+	RecommendationHeader
+	ProductRecommendation
+	ItemDimensions *ItemDimensions `xml:"ItemDimensions"`
+}
+
+type ListingQualityRecommendation struct {
+	RecommendationHeader
+	QualitySet      string `xml:"QualitySet"`
+	DefectGroup     string `xml:"DefectGroup"`
+	DefectAttribute string `xml:"DefectAttribute"`
+}
+
+type GlobalSellingRecommendation struct {
+	RecommendationHeader
+	ProductRecommendation
+	ItemDimensions *ItemDimensions `xml:"ItemDimensions"`
+}
+
+type AdvertisingRecommendation struct {
+	RecommendationHeader
+	BrandName               string          `xml:"BrandName"`
+	ProductCategory         string          `xml:"ProductCategory"`
+	SalesRank               int             `xml:"SalesRank"`
+	YourPricePlusShipping   *CurrencyAmount `xml:"YourPricePlusShipping"`
+	LowestPricePlusShipping *CurrencyAmount `xml:"LowestPricePlusShipping"`
+	AvailableQuantity       int             `xml:"AvailableQuantity"`
+	SalesForTheLast30Days   int             `xml:"SalesForTheLast30Days"`
+}
+
+type ListRecommendationsResult struct {
+	NextToken                     string                         `xml:"NextToken"`
+	InventoryRecommendations      []InventoryRecommendation      `xml:"InventoryRecommendations>member"`
+	SelectionRecommendations      []SelectionRecommendation      `xml:"SelectionRecommendations>member"`
+	PricingRecommendations        []PricingRecommendation        `xml:"PricingRecommendations>member"`
+	FulfillmentRecommendations    []FulfillmentRecommendation    `xml:"FulfillmentRecommendations>member"`
+	ListingQualityRecommendations []ListingQualityRecommendation `xml:"ListingQualityRecommendations>member"`
+	GlobalSellingRecommendations  []GlobalSellingRecommendation  `xml:"GlobalSellingRecommendations>member"`
+	AdvertisingRecommendations    []AdvertisingRecommendation    `xml:"AdvertisingRecommendations>member"`
+}
+
+type listRecommendationsResponse struct {
+	Result           ListRecommendationsResult `xml:"ListRecommendationsResult"`
+	ResponseMetadata ResponseMetadata          `xml:"ResponseMetadata"`
+}
+
+type listRecommendationsByNextTokenResponse struct {
+	Result           ListRecommendationsResult `xml:"ListRecommendationsByNextTokenResult"`
+	ResponseMetadata ResponseMetadata          `xml:"ResponseMetadata"`
+}
+
+// CategoryQuery filters the recommendations of one category, for example
+// ListingQuality with the filter option "QualitySet=Defect".
+type CategoryQuery struct {
+	RecommendationCategory string
+	FilterOptions          []string
+}
+
+// ListRecommendationsRequest limits the results to RecommendationCategory when
+// it is set; otherwise recommendations of every category are returned.
+type ListRecommendationsRequest struct {
+	RecommendationCategory string
+	CategoryQueryList      []CategoryQuery
+}
+
+func (api AmazonMWSAPI) ListRecommendations(req ListRecommendationsRequest) (ListRecommendationsResult, Quota, error) {
+	params := make(map[string]string)
+
+	params["MarketplaceId"] = api.MarketplaceId
+	if req.RecommendationCategory != "" {
+		params["RecommendationCategory"] = req.RecommendationCategory
+	}
+	for i, query := range req.CategoryQueryList {
+		prefix := "CategoryQueryList.CategoryQuery." + strconv.Itoa(i+1)
+		params[prefix+".RecommendationCategory"] = query.RecommendationCategory
+		for j, option := range query.FilterOptions {
+			params[prefix+".FilterOptions.FilterOption."+strconv.Itoa(j+1)] = option
+		}
+	}
+
+	var resp listRecommendationsResponse
+	quota, err := api.fetchAndDecode("ListRecommendations", "/Recommendations/2013-04-01", params, nil, &resp)
+
+	return resp.Result, quota, err
+}
+
+func (api AmazonMWSAPI) ListRecommendationsByNextToken(nextToken string) (ListRecommendationsResult, Quota, error) {
+	params := make(map[string]string)
+	params["NextToken"] = nextToken
+
+	var resp listRecommendationsByNextTokenResponse
+	quota, err := api.fetchAndDecode("ListRecommendationsByNextToken", "/Recommendations/2013-04-01", params, nil, &resp)
+
+	return resp.Result, quota, err
+}
@@ -0,0 +1,52 @@
+package amazonmws
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDecodeListRecommendations(t *testing.T) {
+	body := `<ListRecommendationsResponse xmlns="https://mws.amazonservices.com/Recommendations/2013-04-01">
+  <ListRecommendationsResult>
+    <NextToken>MRgZW55IGNhcm5hbCBwbGVhc3VyZS4=</NextToken>
+    <PricingRecommendations>
+      <member>
+        <RecommendationId>Pricing.B00EXAMPLE.2</RecommendationId>
+        <RecommendationReason>Your price is higher than the lowest price.</RecommendationReason>
+        <LastUpdated>2013-03-04T02:10:32+00:00</LastUpdated>
+        <ItemIdentifier><Asin>B00EXAMPLE</Asin><Sku>SKU-1</Sku></ItemIdentifier>
+        <ItemName>Example</ItemName>
+        <Condition>New</Condition>
+        <FulfillmentChannel>Merchant</FulfillmentChannel>
+        <NumberOfOffers>12</NumberOfOffers>
+        <YourPricePlusShipping><CurrencyCode>USD</CurrencyCode><Amount>20.99</Amount></YourPricePlusShipping>
+        <LowestPricePlusShipping><CurrencyCode>USD</CurrencyCode><Amount>18.49</Amount></LowestPricePlusShipping>
+      </member>
+    </PricingRecommendations>
+    <ListingQualityRecommendations>
+      <member>
+        <RecommendationId>ListingQuality.B00EXAMPLE.1</RecommendationId>
+        <QualitySet>Defect</QualitySet>
+        <DefectAttribute>main_image_url</DefectAttribute>
+        <ItemIdentifier><Asin>B00EXAMPLE</Asin></ItemIdentifier>
+      </member>
+    </ListingQualityRecommendations>
+  </ListRecommendationsResult>
+  <ResponseMetadata><RequestId>4d1b1fbb-5cfc-4d8a-8d6f-6d0e1a9c3c01</RequestId></ResponseMetadata>
+</ListRecommendationsResponse>`
+
+	var resp listRecommendationsResponse
+	err := decodeResponse(body, &resp)
+	assert.Nil(t, err)
+
+	pricing := resp.Result.PricingRecommendations[0]
+	assert.Equal(t, "Pricing.B00EXAMPLE.2", pricing.RecommendationId)
+	assert.Equal(t, "SKU-1", pricing.ItemIdentifier.Sku)
+	assert.Equal(t, 12, pricing.NumberOfOffers)
+	assert.Equal(t, &CurrencyAmount{CurrencyCode: "USD", Amount: "18.49"}, pricing.LowestPricePlusShipping)
+	assert.False(t, pricing.LastUpdated.IsZero())
+
+	quality := resp.Result.ListingQualityRecommendations[0]
+	assert.Equal(t, "Defect", quality.QualitySet)
+	assert.Equal(t, "main_image_url", quality.DefectAttribute)
+}
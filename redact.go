@@ -0,0 +1,206 @@
+package amazonmws
+
+import (
+	"fmt"
+	"github.com/valyala/fasthttp"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Redacted replaces secrets in everything this package prints.
+const Redacted = "[REDACTED]"
+
+// sensitiveParams are the query parameters that are never written out.
+var sensitiveParams = map[string]bool{
+	"AWSAccessKeyId": true,
+	"MWSAuthToken":   true,
+	"Signature":      true,
+}
+
+func redact(s string) string {
+	if s == "" {
+		return ""
+	}
+
+	return Redacted
+}
+
+// RedactParams returns a copy of params with AWSAccessKeyId, MWSAuthToken and
+// Signature masked.
+func RedactParams(params map[string]string) map[string]string {
+	redacted := make(map[string]string, len(params))
+	for k, v := range params {
+		if sensitiveParams[k] {
+			v = redact(v)
+		}
+		redacted[k] = v
+	}
+
+	return redacted
+}
+
+// RedactQuery masks AWSAccessKeyId, MWSAuthToken and Signature in an encoded
+// query string or form body, leaving everything else as it was. A full URL
+// may be passed as well.
+func RedactQuery(query string) string {
+	prefix := ""
+	if i := strings.Index(query, "?"); i >= 0 {
+		prefix, query = query[:i+1], query[i+1:]
+	}
+
+	pairs := strings.Split(query, "&")
+	for i, pair := range pairs {
+		j := strings.Index(pair, "=")
+		if j < 0 {
+			continue
+		}
+		if sensitiveParams[pair[:j]] && j+1 < len(pair) {
+			pairs[i] = pair[:j+1] + Redacted
+		}
+	}
+
+	return prefix + strings.Join(pairs, "&")
+}
+
+// formatRedacted prints the redacted copy v of a value of type typeName for
+// the fmt verb being formatted, so that %v, %+v, %s and %#v all hide secrets.
+func formatRedacted(f fmt.State, verb rune, typeName string, v interface{}) {
+	if verb == 'v' && f.Flag('#') {
+		io.WriteString(f, strings.Replace(fmt.Sprintf("%#v", v), fmt.Sprintf("%T", v), typeName, 1))
+		return
+	}
+
+	directive := "%"
+	for _, flag := range "+-# 0" {
+		if f.Flag(int(flag)) {
+			directive += string(flag)
+		}
+	}
+	if width, ok := f.Width(); ok {
+		directive += strconv.Itoa(width)
+	}
+	if precision, ok := f.Precision(); ok {
+		directive += "." + strconv.Itoa(precision)
+	}
+
+	fmt.Fprintf(f, directive+string(verb), v)
+}
+
+type redactedAPI AmazonMWSAPI
+
+func (api AmazonMWSAPI) redacted() redactedAPI {
+	r := redactedAPI(api)
+	r.AccessKey = redact(r.AccessKey)
+	r.SecretKey = redact(r.SecretKey)
+	r.AuthToken = redact(r.AuthToken)
+	r.ctx = nil
+
+	return r
+}
+
+// String describes the client with its keys and auth token masked.
+func (api AmazonMWSAPI) String() string {
+	return fmt.Sprintf("%+v", api.redacted())
+}
+
+func (api AmazonMWSAPI) GoString() string {
+	return fmt.Sprintf("%#v", api)
+}
+
+func (api AmazonMWSAPI) Format(f fmt.State, verb rune) {
+	formatRedacted(f, verb, "amazonmws.AmazonMWSAPI", api.redacted())
+}
+
+type redactedCredentials Credentials
+
+func (c Credentials) redacted() redactedCredentials {
+	return redactedCredentials{
+		AccessKey: redact(c.AccessKey),
+		SecretKey: redact(c.SecretKey),
+		AuthToken: redact(c.AuthToken),
+	}
+}
+
+func (c Credentials) String() string {
+	return fmt.Sprintf("%+v", c.redacted())
+}
+
+func (c Credentials) GoString() string {
+	return fmt.Sprintf("%#v", c)
+}
+
+func (c Credentials) Format(f fmt.State, verb rune) {
+	formatRedacted(f, verb, "amazonmws.Credentials", c.redacted())
+}
+
+type redactedSQSQueue SQSQueue
+
+func (q SQSQueue) redacted() redactedSQSQueue {
+	r := redactedSQSQueue(q)
+	r.AccessKey = redact(r.AccessKey)
+	r.SecretKey = redact(r.SecretKey)
+
+	return r
+}
+
+func (q SQSQueue) String() string {
+	return fmt.Sprintf("%+v", q.redacted())
+}
+
+func (q SQSQueue) GoString() string {
+	return fmt.Sprintf("%#v", q)
+}
+
+func (q SQSQueue) Format(f fmt.State, verb rune) {
+	formatRedacted(f, verb, "amazonmws.SQSQueue", q.redacted())
+}
+
+// CachingCredentials only shows its provider and TTL, never the cached keys.
+type redactedCachingCredentials struct {
+	Provider CredentialsProvider
+	TTL      string
+}
+
+func (c *CachingCredentials) String() string {
+	return fmt.Sprintf("%+v", redactedCachingCredentials{Provider: c.Provider, TTL: c.TTL.String()})
+}
+
+func (c *CachingCredentials) GoString() string {
+	return fmt.Sprintf("%#v", c)
+}
+
+func (c *CachingCredentials) Format(f fmt.State, verb rune) {
+	formatRedacted(f, verb, "amazonmws.CachingCredentials", redactedCachingCredentials{Provider: c.Provider, TTL: c.TTL.String()})
+}
+
+// dumpExchange writes req and resp to w in a wire-like format with every
+// secret masked. Bodies that are not form encoded, such as feeds, are
+// summarized by their size.
+func dumpExchange(w io.Writer, req *fasthttp.Request, resp *fasthttp.Response, err error) {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "> %s %s\n", req.Header.Method(), RedactQuery(string(req.RequestURI())))
+	req.Header.VisitAll(func(key, value []byte) {
+		fmt.Fprintf(&b, "> %s: %s\n", key, value)
+	})
+	b.WriteString(">\n")
+	if strings.HasPrefix(string(req.Header.Peek("Content-Type")), "application/x-www-form-urlencoded") {
+		fmt.Fprintf(&b, "> %s\n", RedactQuery(string(req.Body())))
+	} else if len(req.Body()) > 0 {
+		fmt.Fprintf(&b, "> [%d bytes]\n", len(req.Body()))
+	}
+
+	if err != nil {
+		fmt.Fprintf(&b, "< error: %v\n", err)
+	} else {
+		fmt.Fprintf(&b, "< %d\n", resp.StatusCode())
+		resp.Header.VisitAll(func(key, value []byte) {
+			fmt.Fprintf(&b, "< %s: %s\n", key, value)
+		})
+		b.WriteString("<\n")
+		fmt.Fprintf(&b, "< %s\n", resp.Body())
+	}
+
+	io.WriteString(w, b.String())
+}
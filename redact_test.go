@@ -0,0 +1,68 @@
+package amazonmws
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"testing"
+)
+
+func TestAmazonMWSAPIFormatRedactsSecrets(t *testing.T) {
+	api := AmazonMWSAPI{
+		AccessKey:   "AKIDSECRETKEYID",
+		SecretKey:   "s3cr3t",
+		AuthToken:   "amzn.mws.token",
+		Host:        "mws.amazonservices.com",
+		SellerId:    "A1SELLER",
+		Credentials: Credentials{AccessKey: "AKIDPROVIDED", SecretKey: "provided-s3cr3t"},
+	}
+
+	for _, format := range []string{"%v", "%+v", "%#v", "%s", "%20v"} {
+		for _, value := range []interface{}{api, &api} {
+			out := fmt.Sprintf(format, value)
+			assert.NotContains(t, out, "AKID", format)
+			assert.NotContains(t, out, "s3cr3t", format)
+			assert.NotContains(t, out, "amzn.mws.token", format)
+			assert.Contains(t, out, "A1SELLER", format)
+		}
+	}
+
+	assert.Contains(t, fmt.Sprintf("%#v", api), "amazonmws.AmazonMWSAPI{AccessKey:\"[REDACTED]\"")
+	assert.Contains(t, fmt.Sprintf("%#v", api), "Credentials:amazonmws.Credentials{")
+	assert.NotContains(t, (&SQSQueue{AccessKey: "AKIDQUEUE", SecretKey: "s3cr3t"}).String(), "s3cr3t")
+	assert.NotContains(t, fmt.Sprintf("%+v", NewCachingCredentials(api.Credentials, 0)), "s3cr3t")
+}
+
+func TestRedactQuery(t *testing.T) {
+	assert.Equal(t,
+		"https://mws.amazonservices.com/?AWSAccessKeyId=[REDACTED]&Action=ListOrders&MWSAuthToken=[REDACTED]&Signature=[REDACTED]&Flag",
+		RedactQuery("https://mws.amazonservices.com/?AWSAccessKeyId=AKID&Action=ListOrders&MWSAuthToken=amzn.mws.token&Signature=abc%3D&Flag"))
+
+	params := RedactParams(map[string]string{"Signature": "abc=", "Action": "ListOrders"})
+	assert.Equal(t, map[string]string{"Signature": Redacted, "Action": "ListOrders"}, params)
+}
+
+func TestDumpExchange(t *testing.T) {
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+
+	req.Header.SetMethodBytes(strPost)
+	req.SetRequestURI("https://mws.amazonservices.com/Products/2011-10-01")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBodyString("AWSAccessKeyId=AKID&Action=GetServiceStatus&Signature=abc%3D")
+	resp.SetStatusCode(200)
+	resp.SetBodyString("<GetServiceStatusResponse/>")
+
+	var buf bytes.Buffer
+	dumpExchange(&buf, req, resp, nil)
+
+	out := buf.String()
+	assert.Contains(t, out, "> POST https://mws.amazonservices.com/Products/2011-10-01\n")
+	assert.Contains(t, out, "> AWSAccessKeyId=[REDACTED]&Action=GetServiceStatus&Signature=[REDACTED]\n")
+	assert.Contains(t, out, "< 200\n")
+	assert.Contains(t, out, "<GetServiceStatusResponse/>")
+	assert.NotContains(t, out, "AKID")
+}
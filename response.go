@@ -0,0 +1,30 @@
+package amazonmws
+
+import (
+	"encoding/xml"
+)
+
+// ResponseMetadata is attached to every successful MWS response.
+type ResponseMetadata struct {
+	RequestId string `xml:"RequestId"`
+}
+
+// checkResponse returns the MWSError described by body, if any, for
+// operations whose successful response carries no result.
+func checkResponse(body string) error {
+	if mwsErr := parseErrorResponse(body); mwsErr != nil {
+		return mwsErr
+	}
+
+	return nil
+}
+
+// decodeResponse unmarshals an MWS response body into v, returning the
+// MWSError instead when Amazon answered with an ErrorResponse document.
+func decodeResponse(body string, v interface{}) error {
+	if mwsErr := parseErrorResponse(body); mwsErr != nil {
+		return mwsErr
+	}
+
+	return xml.Unmarshal([]byte(body), v)
+}
@@ -0,0 +1,314 @@
+package amazonmws
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrRequestStale is returned for a request the Scheduler dropped because its
+// context's deadline would pass before quota is available for it.
+var ErrRequestStale = errors.New("amazonmws: request dropped, its deadline passes before quota is available")
+
+// Priority orders requests waiting for the same quota.
+type Priority int
+
+const (
+	// PriorityBulk is for background work such as catalog refreshes.
+	PriorityBulk Priority = iota
+	// PriorityNormal is used for requests without a priority.
+	PriorityNormal
+	// PriorityInteractive is for requests someone is waiting on.
+	PriorityInteractive
+
+	priorityLevels = int(PriorityInteractive) + 1
+)
+
+type priorityKey struct{}
+
+// WithPriority returns a context that schedules the requests made with it at
+// priority p. Pass it to methods taking a context, or to WithContext.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+func priorityFrom(ctx context.Context) Priority {
+	p, ok := ctx.Value(priorityKey{}).(Priority)
+	if !ok || p < PriorityBulk || p > PriorityInteractive {
+		return PriorityNormal
+	}
+
+	return p
+}
+
+type ticket struct {
+	limiter  *Limiter
+	n        float64
+	deadline time.Time
+
+	wait  time.Duration
+	ready chan error
+}
+
+// Scheduler decides which waiting request is sent next. Higher priorities
+// always go first for the same limiter. A request whose context deadline
+// would pass before its quota frees up is dropped with ErrRequestStale
+// instead of spending quota on a result nobody will use.
+//
+// Amazon grants quota per seller, so sellers never compete for it. What they
+// do share is the client: set MaxInFlight to cap how many requests are sent
+// at once, and sellers take turns for the free slots, so one seller's backlog
+// cannot starve another's.
+//
+// Set it as AmazonMWSAPI.Scheduler and share it between clients:
+//
+//	scheduler := amazonmws.NewScheduler()
+//	scheduler.MaxInFlight = 8
+//	api.Scheduler = scheduler
+//	ctx = amazonmws.WithPriority(ctx, amazonmws.PriorityInteractive)
+//	body, _, err := api.WithContext(ctx).GetCompetitivePricingForASIN(asins)
+//
+// Every request sent to Amazon waits for it, once it has passed the
+// client's middleware.
+type Scheduler struct {
+	// MaxInFlight caps the requests in flight at once across every seller
+	// and action. Zero means no cap.
+	MaxInFlight int
+
+	mu       sync.Mutex
+	queues   [priorityLevels]map[string][]*ticket
+	order    [priorityLevels][]string
+	next     [priorityLevels]int
+	inFlight int
+	timer    *time.Timer
+}
+
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// Wait blocks until limiter has n items for sellerId at the priority in ctx
+// and a slot is free, until ctx is done, or until the request is dropped as
+// stale. On success the caller must call done exactly once when the request
+// has completed, to give its slot back.
+func (s *Scheduler) Wait(ctx context.Context, limiter *Limiter, sellerId string, n int) (done func(), err error) {
+	t := &ticket{limiter: limiter, n: float64(n), ready: make(chan error, 1)}
+	if deadline, ok := ctx.Deadline(); ok {
+		t.deadline = deadline
+	}
+	p := priorityFrom(ctx)
+
+	s.mu.Lock()
+	s.enqueue(p, sellerId, t)
+	s.dispatch()
+	s.mu.Unlock()
+
+	select {
+	case err := <-t.ready:
+		if err != nil {
+			return nil, err
+		}
+	case <-ctx.Done():
+		s.mu.Lock()
+		removed := s.remove(p, sellerId, t)
+		s.mu.Unlock()
+		if !removed && <-t.ready == nil {
+			s.release()
+		}
+		return nil, ctx.Err()
+	}
+
+	if t.wait > 0 {
+		timer := time.NewTimer(t.wait)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		s.release()
+		return nil, err
+	}
+
+	return s.release, nil
+}
+
+// release gives back the slot of a request that has completed.
+func (s *Scheduler) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.inFlight--
+	s.dispatch()
+}
+
+func (s *Scheduler) enqueue(p Priority, sellerId string, t *ticket) {
+	if s.queues[p] == nil {
+		s.queues[p] = make(map[string][]*ticket)
+	}
+	if _, ok := s.queues[p][sellerId]; !ok {
+		s.order[p] = append(s.order[p], sellerId)
+	}
+	s.queues[p][sellerId] = append(s.queues[p][sellerId], t)
+}
+
+// remove takes t out of its queue, reporting false when it has already been
+// granted or dropped.
+func (s *Scheduler) remove(p Priority, sellerId string, t *ticket) bool {
+	queue := s.queues[p][sellerId]
+	for i, queued := range queue {
+		if queued == t {
+			s.queues[p][sellerId] = append(queue[:i:i], queue[i+1:]...)
+			s.prune(p, sellerId)
+			s.dispatch()
+			return true
+		}
+	}
+
+	return false
+}
+
+// prune forgets sellerId's queue once it is empty.
+func (s *Scheduler) prune(p Priority, sellerId string) {
+	if len(s.queues[p][sellerId]) > 0 {
+		return
+	}
+	delete(s.queues[p], sellerId)
+	for i, id := range s.order[p] {
+		if id == sellerId {
+			s.order[p] = append(s.order[p][:i], s.order[p][i+1:]...)
+			if s.next[p] > i {
+				s.next[p]--
+			}
+			break
+		}
+	}
+}
+
+// dispatch grants every waiting request that can have its quota and a slot
+// now and arranges to run again when the next one can have its quota. It must
+// be called with s.mu held.
+func (s *Scheduler) dispatch() {
+	now := time.Now()
+	blocked := make(map[*Limiter]bool)
+	var retry time.Duration
+
+	for p := priorityLevels - 1; p >= 0; p-- {
+		for s.grantNext(Priority(p), now, blocked, &retry) {
+		}
+
+		// Whatever is still queued here goes before lower priorities.
+		for _, queue := range s.queues[p] {
+			for _, t := range queue {
+				blocked[t.limiter] = true
+			}
+		}
+	}
+
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	if retry > 0 {
+		s.timer = time.AfterFunc(retry, func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.dispatch()
+		})
+	}
+}
+
+// grantNext takes the sellers waiting at priority p in turn and grants the
+// first request that can go now, dropping stale ones on the way. It reports
+// whether it took a request off a queue, and lowers retry to the shortest
+// wait for quota it saw.
+func (s *Scheduler) grantNext(p Priority, now time.Time, blocked map[*Limiter]bool, retry *time.Duration) bool {
+	order := s.order[p]
+	for i := 0; i < len(order); i++ {
+		idx := (s.next[p] + i) % len(order)
+		sellerId := order[idx]
+		queue := s.queues[p][sellerId]
+
+		// Requests for the same limiter keep their order.
+		waiting := make(map[*Limiter]bool)
+		for j, t := range queue {
+			if blocked[t.limiter] || waiting[t.limiter] {
+				continue
+			}
+
+			wait := t.limiter.available(t.n)
+			switch {
+			case !t.deadline.IsZero() && !now.Add(wait).Before(t.deadline):
+				t.ready <- ErrRequestStale
+			case wait <= 0 && (s.MaxInFlight <= 0 || s.inFlight < s.MaxInFlight):
+				t.wait = t.limiter.reserve(t.n)
+				s.inFlight++
+				t.ready <- nil
+				s.next[p] = idx + 1
+			default:
+				if wait > 0 && (*retry == 0 || wait < *retry) {
+					*retry = wait
+				}
+				waiting[t.limiter] = true
+				continue
+			}
+
+			s.queues[p][sellerId] = append(queue[:j:j], queue[j+1:]...)
+			s.prune(p, sellerId)
+			return true
+		}
+	}
+
+	return false
+}
+
+// pacer is what a request waits on before it is sent: the action's limiter,
+// reached through the client's Scheduler when it has one.
+type pacer struct {
+	limiter   *Limiter
+	scheduler *Scheduler
+	sellerId  string
+	action    string
+}
+
+func (api AmazonMWSAPI) pacer(action string) pacer {
+	return api.pacerFor(api.SellerId, action, "")
+}
+
+func (api AmazonMWSAPI) pacerFor(sellerId, action, section string) pacer {
+	return pacer{limiter: limiterFor(sellerId, action, section), scheduler: api.Scheduler, sellerId: sellerId, action: action}
+}
+
+// wait blocks until the request may be sent. On success done must be called
+// once it has completed.
+func (p pacer) wait(ctx context.Context, n int) (done func(), err error) {
+	if p.scheduler == nil {
+		if err := p.limiter.Wait(ctx, n); err != nil {
+			return nil, err
+		}
+		return func() {}, nil
+	}
+
+	return p.scheduler.Wait(ctx, p.limiter, p.sellerId, n)
+}
+
+type pacingKey struct{}
+
+type pacing struct {
+	pacer pacer
+	n     int
+}
+
+// withPacing returns a context that makes the request sent with it wait for n
+// items from p first.
+func withPacing(ctx context.Context, p pacer, n int) context.Context {
+	return context.WithValue(ctx, pacingKey{}, pacing{pacer: p, n: n})
+}
+
+func pacingFrom(ctx context.Context) (pacing, bool) {
+	pc, ok := ctx.Value(pacingKey{}).(pacing)
+	return pc, ok
+}
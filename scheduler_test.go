@@ -0,0 +1,190 @@
+package amazonmws
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func (s *Scheduler) queued() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := 0
+	for _, queues := range s.queues {
+		for _, queue := range queues {
+			n += len(queue)
+		}
+	}
+	return n
+}
+
+type queuedRequest struct {
+	limiter  *Limiter
+	sellerId string
+	priority Priority
+}
+
+// grantOrder queues requests on scheduler one after the other, calls start
+// when all of them are queued, and returns the order in which they were let
+// through. Each request completes as soon as it is let through.
+func grantOrder(t *testing.T, scheduler *Scheduler, requests []queuedRequest, start func()) []int {
+	var mu sync.Mutex
+	var order []int
+	var wg sync.WaitGroup
+	for i, r := range requests {
+		wg.Add(1)
+		go func(i int, r queuedRequest) {
+			defer wg.Done()
+			ctx := WithPriority(context.Background(), r.priority)
+			done, err := scheduler.Wait(ctx, r.limiter, r.sellerId, 1)
+			assert.Nil(t, err)
+
+			mu.Lock()
+			order = append(order, i)
+			mu.Unlock()
+			done()
+		}(i, r)
+		for scheduler.queued() < i+1 {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	start()
+	wg.Wait()
+
+	return order
+}
+
+func TestSchedulerServesHigherPriorityFirst(t *testing.T) {
+	limiter := NewLimiter(1, 100*time.Millisecond)
+	limiter.reserve(1)
+
+	order := grantOrder(t, NewScheduler(), []queuedRequest{
+		{limiter, "A1SELLER", PriorityBulk},
+		{limiter, "A1SELLER", PriorityNormal},
+		{limiter, "A1SELLER", PriorityInteractive},
+	}, func() {})
+
+	assert.Equal(t, []int{2, 1, 0}, order)
+}
+
+func TestSchedulerTakesTurnsAcrossSellers(t *testing.T) {
+	scheduler := NewScheduler()
+	scheduler.MaxInFlight = 1
+	done, err := scheduler.Wait(context.Background(), NewLimiter(1, time.Hour), "A0SELLER", 1)
+	assert.Nil(t, err)
+
+	first, second := NewLimiter(10, time.Second), NewLimiter(10, time.Second)
+	requests := []queuedRequest{
+		{first, "A1SELLER", PriorityBulk},
+		{first, "A1SELLER", PriorityBulk},
+		{first, "A1SELLER", PriorityBulk},
+		{second, "A2SELLER", PriorityBulk},
+	}
+	assert.Equal(t, []int{0, 3, 1, 2}, grantOrder(t, scheduler, requests, done))
+}
+
+func TestSchedulerGivesSlotsToHigherPriority(t *testing.T) {
+	scheduler := NewScheduler()
+	scheduler.MaxInFlight = 1
+	done, err := scheduler.Wait(context.Background(), NewLimiter(1, time.Hour), "A0SELLER", 1)
+	assert.Nil(t, err)
+
+	requests := []queuedRequest{
+		{NewLimiter(10, time.Second), "A1SELLER", PriorityBulk},
+		{NewLimiter(10, time.Second), "A2SELLER", PriorityInteractive},
+	}
+	assert.Equal(t, []int{1, 0}, grantOrder(t, scheduler, requests, done))
+}
+
+func TestSchedulerDropsStaleRequests(t *testing.T) {
+	scheduler := NewScheduler()
+	limiter := NewLimiter(1, time.Second)
+	limiter.reserve(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := scheduler.Wait(ctx, limiter, "A1SELLER", 1)
+
+	assert.Equal(t, ErrRequestStale, err)
+	assert.True(t, time.Since(start) < 100*time.Millisecond)
+	assert.True(t, limiter.available(1) > 900*time.Millisecond)
+	assert.Equal(t, 0, scheduler.queued())
+}
+
+func TestSchedulerForgetsCanceledRequests(t *testing.T) {
+	scheduler := NewScheduler()
+	limiter := NewLimiter(1, time.Second)
+	limiter.reserve(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		_, err := scheduler.Wait(ctx, limiter, "A1SELLER", 1)
+		done <- err
+	}()
+	for scheduler.queued() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	assert.Equal(t, context.Canceled, <-done)
+	assert.Equal(t, 0, scheduler.queued())
+}
+
+func TestSchedulerReleasesSlotOfCanceledRequest(t *testing.T) {
+	scheduler := NewScheduler()
+	scheduler.MaxInFlight = 1
+	limiter := NewLimiter(10, time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := scheduler.Wait(ctx, limiter, "A1SELLER", 1)
+	assert.Equal(t, context.Canceled, err)
+
+	done, err := scheduler.Wait(context.Background(), limiter, "A1SELLER", 1)
+	assert.Nil(t, err)
+	done()
+	assert.Equal(t, 0, scheduler.inFlight)
+}
+
+func TestTypedCallsWaitForScheduler(t *testing.T) {
+	scheduler := NewScheduler()
+	scheduler.MaxInFlight = 1
+	done, err := scheduler.Wait(context.Background(), NewLimiter(1, time.Hour), "A0SELLER", 1)
+	assert.Nil(t, err)
+
+	api := AmazonMWSAPI{Host: offlineHost, SellerId: "A1SELLER", Scheduler: scheduler}
+	returned := make(chan error)
+	go func() {
+		_, _, err := api.GetCompetitivePricingForASIN([]string{"B1"})
+		returned <- err
+	}()
+	for scheduler.queued() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	select {
+	case <-returned:
+		t.Fatal("request sent while the scheduler had no free slot")
+	case <-time.After(20 * time.Millisecond):
+	}
+	done()
+	assert.NotNil(t, <-returned)
+	assert.Equal(t, 0, scheduler.queued())
+}
+
+func TestPacerUsesScheduler(t *testing.T) {
+	api := AmazonMWSAPI{SellerId: "A1SELLER", Scheduler: NewScheduler()}
+	p := api.pacer("GetCompetitivePricingForASIN")
+
+	assert.Equal(t, limiterFor("A1SELLER", "GetCompetitivePricingForASIN", ""), p.limiter)
+	done, err := p.wait(WithPriority(context.Background(), PriorityInteractive), 1)
+	assert.Nil(t, err)
+	done()
+	assert.Equal(t, 0, api.Scheduler.inFlight)
+	assert.Equal(t, PriorityNormal, priorityFrom(context.Background()))
+}
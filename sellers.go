@@ -0,0 +1,116 @@
+package amazonmws
+
+import (
+	"context"
+)
+
+// Participation is a marketplace the seller can sell in.
+// HasSellerSuspendedListings is "Yes" or "No".
+type Participation struct {
+	MarketplaceId              string `xml:"MarketplaceId"`
+	SellerId                   string `xml:"SellerId"`
+	HasSellerSuspendedListings string `xml:"HasSellerSuspendedListings"`
+}
+
+// Suspended reports whether the seller has suspended listings in the
+// marketplace.
+func (p Participation) Suspended() bool {
+	return p.HasSellerSuspendedListings == "Yes"
+}
+
+type Marketplace struct {
+	Id                  string `xml:"MarketplaceId"`
+	Name                string `xml:"Name"`
+	DefaultCountryCode  string `xml:"DefaultCountryCode"`
+	DefaultCurrencyCode string `xml:"DefaultCurrencyCode"`
+	DefaultLanguageCode string `xml:"DefaultLanguageCode"`
+	DomainName          string `xml:"DomainName"`
+}
+
+type ListMarketplaceParticipationsResult struct {
+	NextToken      string          `xml:"NextToken"`
+	Participations []Participation `xml:"ListParticipations>Participation"`
+	Marketplaces   []Marketplace   `xml:"ListMarketplaces>Marketplace"`
+}
+
+type listMarketplaceParticipationsResponse struct {
+	Result           ListMarketplaceParticipationsResult `xml:"ListMarketplaceParticipationsResult"`
+	ResponseMetadata ResponseMetadata                    `xml:"ResponseMetadata"`
+}
+
+type listMarketplaceParticipationsByNextTokenResponse struct {
+	Result           ListMarketplaceParticipationsResult `xml:"ListMarketplaceParticipationsByNextTokenResult"`
+	ResponseMetadata ResponseMetadata                    `xml:"ResponseMetadata"`
+}
+
+func (api AmazonMWSAPI) ListMarketplaceParticipations() (ListMarketplaceParticipationsResult, Quota, error) {
+	params := make(map[string]string)
+
+	var resp listMarketplaceParticipationsResponse
+	quota, err := api.fetchAndDecode("ListMarketplaceParticipations", "/Sellers/2011-07-01", params, nil, &resp)
+
+	return resp.Result, quota, err
+}
+
+func (api AmazonMWSAPI) ListMarketplaceParticipationsByNextToken(nextToken string) (ListMarketplaceParticipationsResult, Quota, error) {
+	params := make(map[string]string)
+	params["NextToken"] = nextToken
+
+	var resp listMarketplaceParticipationsByNextTokenResponse
+	quota, err := api.fetchAndDecode("ListMarketplaceParticipationsByNextToken", "/Sellers/2011-07-01", params, nil, &resp)
+
+	return resp.Result, quota, err
+}
+
+// AllMarketplaceParticipations follows NextToken until every participation
+// and marketplace has been listed.
+func (api AmazonMWSAPI) AllMarketplaceParticipations(ctx context.Context) (ListMarketplaceParticipationsResult, error) {
+	pacer := api.pacer("ListMarketplaceParticipations")
+
+	var all ListMarketplaceParticipationsResult
+	nextToken := ""
+	for {
+		var result ListMarketplaceParticipationsResult
+		_, _, err := fetchWithRetry(ctx, api.telemetry(), "ListMarketplaceParticipations", pacer, 1, func(ctx context.Context) (string, Quota, error) {
+			var quota Quota
+			var err error
+			if nextToken == "" {
+				result, quota, err = api.WithContext(ctx).ListMarketplaceParticipations()
+			} else {
+				result, quota, err = api.WithContext(ctx).ListMarketplaceParticipationsByNextToken(nextToken)
+			}
+			return "", quota, err
+		})
+		if err != nil {
+			return ListMarketplaceParticipationsResult{}, err
+		}
+
+		all.Participations = append(all.Participations, result.Participations...)
+		all.Marketplaces = append(all.Marketplaces, result.Marketplaces...)
+
+		if result.NextToken == "" {
+			return all, nil
+		}
+		nextToken = result.NextToken
+	}
+}
+
+// MarketplaceClients returns a copy of api for every marketplace the seller
+// participates in, keyed by marketplace ID, with MarketplaceId set
+// accordingly. Participations only cover the region of api.Host, so the
+// copies keep the same Host and credentials.
+func (api AmazonMWSAPI) MarketplaceClients(ctx context.Context) (map[string]AmazonMWSAPI, error) {
+	result, err := api.AllMarketplaceParticipations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	clients := make(map[string]AmazonMWSAPI, len(result.Participations))
+	for _, p := range result.Participations {
+		client := api
+		client.MarketplaceId = p.MarketplaceId
+		clients[p.MarketplaceId] = client
+	}
+
+	return clients, nil
+}
@@ -0,0 +1,90 @@
+package amazonmws
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDecodeListMarketplaceParticipations(t *testing.T) {
+	body := `<ListMarketplaceParticipationsResponse xmlns="https://mws.amazonservices.com/Sellers/2011-07-01">
+  <ListMarketplaceParticipationsResult>
+    <NextToken>MRgZW55IGNhcm5hbCBwbGVhc3VyZS4=</NextToken>
+    <ListParticipations>
+      <Participation>
+        <MarketplaceId>ATVPDKIKX0DER</MarketplaceId>
+        <SellerId>A135KKEKJAIBJ56</SellerId>
+        <HasSellerSuspendedListings>No</HasSellerSuspendedListings>
+      </Participation>
+      <Participation>
+        <MarketplaceId>A2EUQ1WTGCTBG2</MarketplaceId>
+        <SellerId>A135KKEKJAIBJ56</SellerId>
+        <HasSellerSuspendedListings>Yes</HasSellerSuspendedListings>
+      </Participation>
+    </ListParticipations>
+    <ListMarketplaces>
+      <Marketplace>
+        <MarketplaceId>ATVPDKIKX0DER</MarketplaceId>
+        <DefaultCountryCode>US</DefaultCountryCode>
+        <DomainName>www.amazon.com</DomainName>
+        <Name>Amazon.com</Name>
+        <DefaultCurrencyCode>USD</DefaultCurrencyCode>
+        <DefaultLanguageCode>en_US</DefaultLanguageCode>
+      </Marketplace>
+    </ListMarketplaces>
+  </ListMarketplaceParticipationsResult>
+  <ResponseMetadata><RequestId>efeab958-74e1-4a5c-9bd7-41d2a1bd0a3f</RequestId></ResponseMetadata>
+</ListMarketplaceParticipationsResponse>`
+
+	var resp listMarketplaceParticipationsResponse
+	err := decodeResponse(body, &resp)
+	assert.Nil(t, err)
+
+	result := resp.Result
+	assert.Equal(t, "MRgZW55IGNhcm5hbCBwbGVhc3VyZS4=", result.NextToken)
+	assert.Len(t, result.Participations, 2)
+	assert.False(t, result.Participations[0].Suspended())
+	assert.True(t, result.Participations[1].Suspended())
+	assert.Equal(t, Marketplace{
+		Id:                  "ATVPDKIKX0DER",
+		Name:                "Amazon.com",
+		DefaultCountryCode:  "US",
+		DefaultCurrencyCode: "USD",
+		DefaultLanguageCode: "en_US",
+		DomainName:          "www.amazon.com",
+	}, result.Marketplaces[0])
+}
+
+func TestAllMarketplaceParticipationsFollowsNextToken(t *testing.T) {
+	pages := map[string]string{
+		"ListMarketplaceParticipations": `<ListMarketplaceParticipationsResponse><ListMarketplaceParticipationsResult>
+  <NextToken>page-2</NextToken>
+  <ListParticipations><Participation><MarketplaceId>ATVPDKIKX0DER</MarketplaceId></Participation></ListParticipations>
+</ListMarketplaceParticipationsResult></ListMarketplaceParticipationsResponse>`,
+		"ListMarketplaceParticipationsByNextToken": `<ListMarketplaceParticipationsByNextTokenResponse><ListMarketplaceParticipationsByNextTokenResult>
+  <ListParticipations><Participation><MarketplaceId>A2EUQ1WTGCTBG2</MarketplaceId></Participation></ListParticipations>
+</ListMarketplaceParticipationsByNextTokenResult></ListMarketplaceParticipationsByNextTokenResponse>`,
+	}
+
+	var seen []*Request
+	amazon := func(next RoundTripper) RoundTripper {
+		return RoundTripperFunc(func(req *Request) (*Response, error) {
+			seen = append(seen, req)
+			return &Response{StatusCode: 200, Body: pages[req.Action]}, nil
+		})
+	}
+	api := AmazonMWSAPI{Host: "mws.amazonservices.com", SellerId: "A1SELLER", Middleware: []Middleware{amazon}}
+
+	clients, err := api.MarketplaceClients(context.Background())
+
+	assert.Nil(t, err)
+	assert.Len(t, seen, 2)
+	assert.Equal(t, "/Sellers/2011-07-01", seen[0].Section)
+	assert.NotContains(t, seen[0].Params, "NextToken")
+	assert.Equal(t, "ListMarketplaceParticipationsByNextToken", seen[1].Action)
+	assert.Equal(t, "page-2", seen[1].Params["NextToken"])
+
+	assert.Len(t, clients, 2)
+	assert.Equal(t, "A2EUQ1WTGCTBG2", clients["A2EUQ1WTGCTBG2"].MarketplaceId)
+	assert.Equal(t, "A1SELLER", clients["A2EUQ1WTGCTBG2"].SellerId)
+}
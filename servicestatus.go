@@ -0,0 +1,143 @@
+package amazonmws
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+const (
+	ServiceStatusGreen  = "GREEN"
+	ServiceStatusGreenI = "GREEN_I"
+	ServiceStatusYellow = "YELLOW"
+	ServiceStatusRed    = "RED"
+)
+
+type ServiceStatusMessage struct {
+	Locale string `xml:"Locale"`
+	Text   string `xml:"Text"`
+}
+
+// ServiceStatus is the operational status of one API section. GREEN_I means
+// the section is available but Amazon has posted an informational message.
+type ServiceStatus struct {
+	Status    string                 `xml:"Status"`
+	Timestamp time.Time              `xml:"Timestamp"`
+	MessageId string                 `xml:"MessageId"`
+	Messages  []ServiceStatusMessage `xml:"Messages>Message"`
+}
+
+// Available reports whether the section is GREEN or GREEN_I.
+func (s ServiceStatus) Available() bool {
+	return s.Status == ServiceStatusGreen || s.Status == ServiceStatusGreenI
+}
+
+type getServiceStatusResponse struct {
+	Result           ServiceStatus    `xml:"GetServiceStatusResult"`
+	ResponseMetadata ResponseMetadata `xml:"ResponseMetadata"`
+}
+
+// Sections returns the path of every API section the client supports, such
+// as "/Products/2011-10-01", in sorted order.
+func Sections() []string {
+	versionsMu.RLock()
+	defer versionsMu.RUnlock()
+
+	sections := make([]string, 0, len(versions))
+	for section := range versions {
+		sections = append(sections, section)
+	}
+	sort.Strings(sections)
+
+	return sections
+}
+
+// GetServiceStatus returns the status of the API section at the given path,
+// for example "/Products/2011-10-01". Every section has its own quota of two
+// requests, restored at one every five minutes.
+func (api AmazonMWSAPI) GetServiceStatus(section string) (ServiceStatus, Quota, error) {
+	params := make(map[string]string)
+
+	var resp getServiceStatusResponse
+	quota, err := api.fetchAndDecode("GetServiceStatus", section, params, nil, &resp)
+
+	return resp.Result, quota, err
+}
+
+// SectionHealth is the outcome of probing one section.
+type SectionHealth struct {
+	Section string
+	Status  ServiceStatus
+	Err     error
+}
+
+// Healthy reports whether the section answered and is GREEN or GREEN_I.
+func (h SectionHealth) Healthy() bool {
+	return h.Err == nil && h.Status.Available()
+}
+
+// HealthReport is the result of HealthCheck, with one entry per section in
+// the order returned by Sections.
+type HealthReport struct {
+	Sections []SectionHealth
+}
+
+// Healthy reports whether every section is healthy.
+func (r HealthReport) Healthy() bool {
+	for _, s := range r.Sections {
+		if !s.Healthy() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Unhealthy returns the sections that failed to answer or are YELLOW or RED.
+func (r HealthReport) Unhealthy() []SectionHealth {
+	var unhealthy []SectionHealth
+	for _, s := range r.Sections {
+		if !s.Healthy() {
+			unhealthy = append(unhealthy, s)
+		}
+	}
+
+	return unhealthy
+}
+
+// HealthCheck calls GetServiceStatus on every supported section concurrently
+// and reports the results. Sections that have not answered when ctx is done
+// are reported with ctx's error.
+func (api AmazonMWSAPI) HealthCheck(ctx context.Context) HealthReport {
+	sections := Sections()
+
+	results := make(chan SectionHealth, len(sections))
+	for _, section := range sections {
+		go func(section string) {
+			status, _, err := api.WithContext(ctx).GetServiceStatus(section)
+			results <- SectionHealth{Section: section, Status: status, Err: err}
+		}(section)
+	}
+
+	collected := make(map[string]SectionHealth, len(sections))
+wait:
+	for len(collected) < len(sections) {
+		select {
+		case result := <-results:
+			collected[result.Section] = result
+		case <-ctx.Done():
+			break wait
+		}
+	}
+
+	report := HealthReport{Sections: make([]SectionHealth, len(sections))}
+	for i, section := range sections {
+		result, ok := collected[section]
+		if !ok {
+			result = SectionHealth{Section: section, Err: ctx.Err()}
+		}
+		report.Sections[i] = result
+	}
+
+	return report
+}
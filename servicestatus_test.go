@@ -0,0 +1,111 @@
+package amazonmws
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestDecodeGetServiceStatus(t *testing.T) {
+	body := `<GetServiceStatusResponse xmlns="http://mws.amazonservices.com/schema/Products/2011-10-01">
+  <GetServiceStatusResult>
+    <Status>GREEN_I</Status>
+    <Timestamp>2013-09-05T18:12:21.687Z</Timestamp>
+    <MessageId>173964729I</MessageId>
+    <Messages>
+      <Message><Locale>en_US</Locale><Text>We are experiencing high latency in UK because of heavy traffic.</Text></Message>
+    </Messages>
+  </GetServiceStatusResult>
+  <ResponseMetadata><RequestId>d80c6c7b-f7c7-4fa7-bdd7-854711cb3bcc</RequestId></ResponseMetadata>
+</GetServiceStatusResponse>`
+
+	var resp getServiceStatusResponse
+	err := decodeResponse(body, &resp)
+	assert.Nil(t, err)
+	assert.Equal(t, ServiceStatusGreenI, resp.Result.Status)
+	assert.True(t, resp.Result.Available())
+	assert.Equal(t, "en_US", resp.Result.Messages[0].Locale)
+}
+
+func TestHealthCheckCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	api := AmazonMWSAPI{Host: "mws.invalid"}
+	report := api.HealthCheck(ctx)
+
+	assert.Len(t, report.Sections, len(Sections()))
+	assert.False(t, report.Healthy())
+	for _, s := range report.Unhealthy() {
+		assert.NotNil(t, s.Err)
+	}
+}
+
+func TestGetServiceStatus(t *testing.T) {
+	body := `<GetServiceStatusResponse xmlns="http://mws.amazonservices.com/schema/Orders/2013-09-01">
+  <GetServiceStatusResult>
+    <Status>RED</Status>
+    <Timestamp>2013-09-05T18:12:21.687Z</Timestamp>
+    <MessageId>173964729I</MessageId>
+    <Messages>
+      <Message><Locale>en_US</Locale><Text>Orders are delayed.</Text></Message>
+      <Message><Locale>de_DE</Locale><Text>Bestellungen sind verzoegert.</Text></Message>
+    </Messages>
+  </GetServiceStatusResult>
+  <ResponseMetadata><RequestId>d80c6c7b-f7c7-4fa7-bdd7-854711cb3bcc</RequestId></ResponseMetadata>
+</GetServiceStatusResponse>`
+
+	var seen []*Request
+	api := AmazonMWSAPI{Host: "mws.amazonservices.com", Middleware: []Middleware{amazonReturning(body, &seen)}}
+
+	status, _, err := api.GetServiceStatus("/Finances/2015-05-01")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "GetServiceStatus", seen[0].Action)
+	assert.Equal(t, "/Finances/2015-05-01", seen[0].Section)
+	assert.Equal(t, ServiceStatusRed, status.Status)
+	assert.False(t, status.Available())
+	assert.Equal(t, time.Date(2013, 9, 5, 18, 12, 21, 687000000, time.UTC), status.Timestamp)
+	assert.Equal(t, "173964729I", status.MessageId)
+	assert.Equal(t, []ServiceStatusMessage{
+		{Locale: "en_US", Text: "Orders are delayed."},
+		{Locale: "de_DE", Text: "Bestellungen sind verzoegert."},
+	}, status.Messages)
+}
+
+func TestHealthCheckReportsUnhealthySections(t *testing.T) {
+	amazon := func(next RoundTripper) RoundTripper {
+		return RoundTripperFunc(func(req *Request) (*Response, error) {
+			status := ServiceStatusGreen
+			if req.Section == "/Reports/2009-01-01" {
+				status = ServiceStatusYellow
+			}
+			return &Response{StatusCode: 200, Body: `<GetServiceStatusResponse><GetServiceStatusResult><Status>` + status + `</Status></GetServiceStatusResult></GetServiceStatusResponse>`}, nil
+		})
+	}
+	api := AmazonMWSAPI{Host: "mws.amazonservices.com", Middleware: []Middleware{amazon}}
+
+	report := api.HealthCheck(context.Background())
+
+	assert.False(t, report.Healthy())
+	unhealthy := report.Unhealthy()
+	assert.Len(t, unhealthy, 1)
+	assert.Equal(t, "/Reports/2009-01-01", unhealthy[0].Section)
+	assert.Equal(t, ServiceStatusYellow, unhealthy[0].Status.Status)
+	assert.Nil(t, unhealthy[0].Err)
+}
+
+func TestGetServiceStatusPacedPerSection(t *testing.T) {
+	products := limiterFor("status-test", "GetServiceStatus", "/Products/2011-10-01")
+	finances := limiterFor("status-test", "GetServiceStatus", "/Finances/2015-05-01")
+
+	assert.NotEqual(t, products, finances)
+	assert.Equal(t, products, limiterFor("status-test", "GetServiceStatus", "/Products/2011-10-01"))
+	assert.Equal(t, float64(2), products.max)
+	assert.Equal(t, 5*time.Minute, products.restore)
+
+	products.Backoff()
+	assert.Equal(t, float64(2), finances.tokens)
+	assert.Equal(t, limiterFor("status-test", "ListInventorySupply", "/Products/2011-10-01"), limiterFor("status-test", "ListInventorySupply", "/Finances/2015-05-01"))
+}
@@ -0,0 +1,118 @@
+package amazonmws
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"time"
+)
+
+type TaxClassification struct {
+	Name  string `xml:"Name"`
+	Value string `xml:"Value"`
+}
+
+type TaxInfo struct {
+	CompanyLegalName   string              `xml:"CompanyLegalName"`
+	TaxingRegion       string              `xml:"TaxingRegion"`
+	TaxClassifications []TaxClassification `xml:"TaxClassifications>TaxClassification"`
+}
+
+type InvoicingAddress struct {
+	Name          string `xml:"Name"`
+	AddressLine1  string `xml:"AddressLine1"`
+	AddressLine2  string `xml:"AddressLine2"`
+	AddressLine3  string `xml:"AddressLine3"`
+	City          string `xml:"City"`
+	County        string `xml:"County"`
+	District      string `xml:"District"`
+	StateOrRegion string `xml:"StateOrRegion"`
+	PostalCode    string `xml:"PostalCode"`
+	CountryCode   string `xml:"CountryCode"`
+	Phone         string `xml:"Phone"`
+	AddressType   string `xml:"AddressType"`
+}
+
+type InvoicingShipmentItem struct {
+	ASIN              string          `xml:"ASIN"`
+	SellerSKU         string          `xml:"SellerSKU"`
+	OrderItemId       string          `xml:"OrderItemId"`
+	Title             string          `xml:"Title"`
+	QuantityOrdered   int             `xml:"QuantityOrdered"`
+	ItemPrice         *CurrencyAmount `xml:"ItemPrice"`
+	ShippingPrice     *CurrencyAmount `xml:"ShippingPrice"`
+	GiftWrapPrice     *CurrencyAmount `xml:"GiftWrapPrice"`
+	ShippingDiscount  *CurrencyAmount `xml:"ShippingDiscount"`
+	PromotionDiscount *CurrencyAmount `xml:"PromotionDiscount"`
+	SerialNumbers     []string        `xml:"SerialNumbers>SerialNumber"`
+}
+
+// FBAOutboundShipmentDetail has what a seller needs to issue the invoice for
+// an FBA shipment in marketplaces, such as Brazil, that require one.
+type FBAOutboundShipmentDetail struct {
+	WarehouseId          string                  `xml:"WarehouseId"`
+	AmazonOrderId        string                  `xml:"AmazonOrderId"`
+	AmazonShipmentId     string                  `xml:"AmazonShipmentId"`
+	PurchaseDate         time.Time               `xml:"PurchaseDate"`
+	ShippingAddress      InvoicingAddress        `xml:"ShippingAddress"`
+	PaymentMethodDetails []string                `xml:"PaymentMethodDetails>PaymentMethodDetail"`
+	MarketplaceId        string                  `xml:"MarketplaceId"`
+	SellerId             string                  `xml:"SellerId"`
+	BuyerName            string                  `xml:"BuyerName"`
+	BuyerCounty          string                  `xml:"BuyerCounty"`
+	BuyerTaxInfo         *TaxInfo                `xml:"BuyerTaxInfo"`
+	MarketplaceTaxInfo   *TaxInfo                `xml:"MarketplaceTaxInfo"`
+	SellerDisplayName    string                  `xml:"SellerDisplayName"`
+	ShipmentItems        []InvoicingShipmentItem `xml:"ShipmentItems>ShipmentItem"`
+}
+
+type ShipmentInvoiceStatus struct {
+	AmazonShipmentId string `xml:"AmazonShipmentId"`
+	InvoiceStatus    string `xml:"InvoiceStatus"`
+}
+
+type getFBAOutboundShipmentDetailResponse struct {
+	ShipmentDetail   FBAOutboundShipmentDetail `xml:"GetFBAOutboundShipmentDetailResult>ShipmentDetail"`
+	ResponseMetadata ResponseMetadata          `xml:"ResponseMetadata"`
+}
+
+type getFBAOutboundShipmentInvoiceStatusResponse struct {
+	Shipments        []ShipmentInvoiceStatus `xml:"GetFBAOutboundShipmentInvoiceStatusResult>Shipments>Shipment"`
+	ResponseMetadata ResponseMetadata        `xml:"ResponseMetadata"`
+}
+
+func (api AmazonMWSAPI) GetFBAOutboundShipmentDetail(amazonShipmentId string) (FBAOutboundShipmentDetail, Quota, error) {
+	params := make(map[string]string)
+
+	params["MarketplaceId"] = api.MarketplaceId
+	params["AmazonShipmentId"] = amazonShipmentId
+
+	var resp getFBAOutboundShipmentDetailResponse
+	quota, err := api.fetchAndDecode("GetFBAOutboundShipmentDetail", "/ShipmentInvoicing/2018-09-01", params, nil, &resp)
+
+	return resp.ShipmentDetail, quota, err
+}
+
+// SubmitFBAOutboundShipmentInvoice uploads the invoice XML for a shipment.
+// The invoice is sent as the request body, like a feed.
+func (api AmazonMWSAPI) SubmitFBAOutboundShipmentInvoice(amazonShipmentId string, invoice []byte) (Quota, error) {
+	params := make(map[string]string)
+
+	hash := md5.Sum(invoice)
+	params["MarketplaceId"] = api.MarketplaceId
+	params["AmazonShipmentId"] = amazonShipmentId
+	params["ContentMD5Value"] = base64.StdEncoding.EncodeToString(hash[:])
+
+	return api.fetchAndDecode("SubmitFBAOutboundShipmentInvoice", "/ShipmentInvoicing/2018-09-01", params, invoice, nil)
+}
+
+func (api AmazonMWSAPI) GetFBAOutboundShipmentInvoiceStatus(amazonShipmentId string) ([]ShipmentInvoiceStatus, Quota, error) {
+	params := make(map[string]string)
+
+	params["MarketplaceId"] = api.MarketplaceId
+	params["AmazonShipmentId"] = amazonShipmentId
+
+	var resp getFBAOutboundShipmentInvoiceStatusResponse
+	quota, err := api.fetchAndDecode("GetFBAOutboundShipmentInvoiceStatus", "/ShipmentInvoicing/2018-09-01", params, nil, &resp)
+
+	return resp.Shipments, quota, err
+}
@@ -0,0 +1,50 @@
+package amazonmws
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDecodeGetFBAOutboundShipmentDetail(t *testing.T) {
+	body := `<GetFBAOutboundShipmentDetailResponse xmlns="https://mws.amazonservices.com/ShipmentInvoicing/2018-09-01">
+  <GetFBAOutboundShipmentDetailResult>
+    <ShipmentDetail>
+      <WarehouseId>GRU5</WarehouseId>
+      <AmazonOrderId>701-1234567-1234567</AmazonOrderId>
+      <AmazonShipmentId>DKMKLXJmN</AmazonShipmentId>
+      <PurchaseDate>2018-09-10T15:04:05Z</PurchaseDate>
+      <ShippingAddress><Name>Maria Silva</Name><City>São Paulo</City><StateOrRegion>SP</StateOrRegion><CountryCode>BR</CountryCode></ShippingAddress>
+      <PaymentMethodDetails><PaymentMethodDetail>CreditCard</PaymentMethodDetail><PaymentMethodDetail>GiftCertificate</PaymentMethodDetail></PaymentMethodDetails>
+      <MarketplaceId>A2Q3Y263D00KWC</MarketplaceId>
+      <BuyerTaxInfo>
+        <TaxingRegion>SP</TaxingRegion>
+        <TaxClassifications><TaxClassification><Name>CSTNumber</Name><Value>123</Value></TaxClassification></TaxClassifications>
+      </BuyerTaxInfo>
+      <ShipmentItems>
+        <ShipmentItem>
+          <ASIN>B00EXAMPLE</ASIN>
+          <SellerSKU>SKU-1</SellerSKU>
+          <QuantityOrdered>2</QuantityOrdered>
+          <ItemPrice><CurrencyCode>BRL</CurrencyCode><Amount>99.90</Amount></ItemPrice>
+          <SerialNumbers><SerialNumber>SN-1</SerialNumber></SerialNumbers>
+        </ShipmentItem>
+      </ShipmentItems>
+    </ShipmentDetail>
+  </GetFBAOutboundShipmentDetailResult>
+  <ResponseMetadata><RequestId>0f1e2d3c-4b5a-6978-8796-a5b4c3d2e1f0</RequestId></ResponseMetadata>
+</GetFBAOutboundShipmentDetailResponse>`
+
+	var resp getFBAOutboundShipmentDetailResponse
+	err := decodeResponse(body, &resp)
+	assert.Nil(t, err)
+
+	detail := resp.ShipmentDetail
+	assert.Equal(t, "DKMKLXJmN", detail.AmazonShipmentId)
+	assert.Equal(t, "São Paulo", detail.ShippingAddress.City)
+	assert.Equal(t, []string{"CreditCard", "GiftCertificate"}, detail.PaymentMethodDetails)
+	assert.Equal(t, []TaxClassification{{Name: "CSTNumber", Value: "123"}}, detail.BuyerTaxInfo.TaxClassifications)
+	assert.Nil(t, detail.MarketplaceTaxInfo)
+	assert.Equal(t, 2, detail.ShipmentItems[0].QuantityOrdered)
+	assert.Equal(t, &CurrencyAmount{CurrencyCode: "BRL", Amount: "99.90"}, detail.ShipmentItems[0].ItemPrice)
+	assert.Equal(t, []string{"SN-1"}, detail.ShipmentItems[0].SerialNumbers)
+}
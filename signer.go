@@ -0,0 +1,146 @@
+package amazonmws
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Signer computes AWS Signature Version 2 signatures as MWS expects them.
+// The algorithm is taken from the SignatureMethod parameter, HmacSHA256 or
+// HmacSHA1, and defaults to HmacSHA256.
+type Signer struct {
+	SecretKey string
+}
+
+// CanonicalQuery returns params sorted by key in byte order, with keys and
+// values percent-encoded per RFC 3986: everything except A-Z, a-z, 0-9, '-',
+// '_', '.' and '~' is escaped as %XX with upper-case hex, including spaces.
+// The Signature parameter is left out.
+func CanonicalQuery(params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		if k != "Signature" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = percentEncode(k) + "=" + percentEncode(params[k])
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+func percentEncode(s string) string {
+	const hex = "0123456789ABCDEF"
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hex[c>>4])
+		b.WriteByte(hex[c&15])
+	}
+
+	return b.String()
+}
+
+// StringToSign returns the exact text that Sign hashes, which is what to
+// compare against the StringToSign Amazon echoes in a SignatureDoesNotMatch
+// error.
+func (s Signer) StringToSign(method, host, path string, params map[string]string) string {
+	if path == "" {
+		path = "/"
+	}
+
+	return strings.ToUpper(method) + "\n" + strings.ToLower(host) + "\n" + path + "\n" + CanonicalQuery(params)
+}
+
+// Sign returns the base64 signature of a request to host and path with the
+// given parameters.
+func (s Signer) Sign(method, host, path string, params map[string]string) (string, error) {
+	var newHash func() hash.Hash
+	switch params["SignatureMethod"] {
+	case "", "HmacSHA256":
+		newHash = sha256.New
+	case "HmacSHA1":
+		newHash = sha1.New
+	default:
+		return "", fmt.Errorf("amazonmws: unsupported SignatureMethod %s", params["SignatureMethod"])
+	}
+
+	mac := hmac.New(newHash, []byte(s.SecretKey))
+	mac.Write([]byte(s.StringToSign(method, host, path, params)))
+
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// SignQuery returns the canonical query for params with the Signature
+// parameter appended, ready to be used as a GET query string or POST body.
+func (s Signer) SignQuery(method, host, path string, params map[string]string) (string, error) {
+	signature, err := s.Sign(method, host, path, params)
+	if err != nil {
+		return "", err
+	}
+
+	return CanonicalQuery(params) + "&Signature=" + percentEncode(signature), nil
+}
+
+// SignURL signs u as a GET request, replacing its query with the canonical
+// query and Signature. A parameter without '=' is signed with an empty value.
+func (s Signer) SignURL(u *url.URL) (string, error) {
+	params, err := parseRawQuery(u.RawQuery)
+	if err != nil {
+		return "", err
+	}
+
+	query, err := s.SignQuery("GET", u.Host, u.Path, params)
+	if err != nil {
+		return "", err
+	}
+	u.RawQuery = query
+
+	return u.String(), nil
+}
+
+// parseRawQuery splits a query string into its decoded parameters. A
+// parameter without '=' gets an empty value, and unlike url.ParseQuery only
+// '&' separates parameters and '+' is kept as a plus sign, as it is in a
+// SellerSKU, rather than read as a space.
+func parseRawQuery(rawQuery string) (map[string]string, error) {
+	params := make(map[string]string)
+	for _, pair := range strings.Split(rawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+
+		key, value := pair, ""
+		if i := strings.Index(pair, "="); i >= 0 {
+			key, value = pair[:i], pair[i+1:]
+		}
+
+		var err error
+		if key, err = url.PathUnescape(key); err != nil {
+			return nil, err
+		}
+		if value, err = url.PathUnescape(value); err != nil {
+			return nil, err
+		}
+		params[key] = value
+	}
+
+	return params, nil
+}
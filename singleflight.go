@@ -0,0 +1,135 @@
+package amazonmws
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// singleflightIgnored are left out when comparing requests: they differ
+// between otherwise identical requests made moments apart.
+var singleflightIgnored = map[string]bool{
+	"Signature": true,
+	"Timestamp": true,
+}
+
+type flight struct {
+	done chan struct{}
+	resp *Response
+	err  error
+}
+
+// Singleflight collapses identical requests that are in flight at the same
+// time into one call to Amazon and hands its response to every caller, so
+// workers asking for the same data at once spend quota only once. Requests
+// are identical when their action, section, body and parameters other than
+// Timestamp and Signature match. Share one Singleflight between the clients
+// that should be deduplicated and install it as middleware:
+//
+//	flights := &amazonmws.Singleflight{}
+//	api.Middleware = append(api.Middleware, flights.Middleware)
+//
+// The zero value is ready to use.
+type Singleflight struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+	shared  int64
+}
+
+// Shared returns how many requests were answered by another caller's call.
+func (g *Singleflight) Shared() int64 {
+	return atomic.LoadInt64(&g.shared)
+}
+
+// Middleware sends the first of a set of identical requests through next and
+// makes the others wait for its response. The shared call does not stop when
+// the caller that started it gives up, so the others still get the response;
+// each caller whose context is done stops waiting with its context's error.
+// Requests wait for quota only once they are sent, so the waiting callers do
+// not spend any.
+func (g *Singleflight) Middleware(next RoundTripper) RoundTripper {
+	return RoundTripperFunc(func(req *Request) (*Response, error) {
+		key := matchKey(req.Action, req.Section, req.Params, string(req.Body), singleflightIgnored)
+
+		g.mu.Lock()
+		if g.flights == nil {
+			g.flights = make(map[string]*flight)
+		}
+		if f, ok := g.flights[key]; ok {
+			g.mu.Unlock()
+			atomic.AddInt64(&g.shared, 1)
+			return f.wait(req)
+		}
+		f := &flight{done: make(chan struct{})}
+		g.flights[key] = f
+		g.mu.Unlock()
+
+		shared := *req
+		shared.Params = make(map[string]string, len(req.Params))
+		for k, v := range req.Params {
+			shared.Params[k] = v
+		}
+		if req.Context != nil {
+			shared.Context = detachedContext{req.Context}
+		}
+		go f.run(next, &shared, func() {
+			g.mu.Lock()
+			delete(g.flights, key)
+			g.mu.Unlock()
+		})
+
+		return f.wait(req)
+	})
+}
+
+// run sends req through next and then lets the waiting callers go, even when
+// next panics, in which case they all get the panic as an error.
+func (f *flight) run(next RoundTripper, req *Request, land func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			f.resp, f.err = nil, fmt.Errorf("amazonmws: %s %s panicked: %v", req.Action, req.Section, r)
+		}
+		land()
+		close(f.done)
+	}()
+
+	f.resp, f.err = next.RoundTrip(req)
+}
+
+func (f *flight) wait(req *Request) (*Response, error) {
+	if req.Context == nil {
+		<-f.done
+		return f.response()
+	}
+
+	select {
+	case <-f.done:
+		return f.response()
+	case <-req.Context.Done():
+		return nil, req.Context.Err()
+	}
+}
+
+// detachedContext carries the values of its parent but never ends, so the
+// shared call outlives the caller that started it.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool)         { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}               { return nil }
+func (detachedContext) Err() error                          { return nil }
+func (c detachedContext) Value(key interface{}) interface{} { return c.parent.Value(key) }
+
+// response gives every caller its own copy, so middleware further out may
+// change it without affecting the others.
+func (f *flight) response() (*Response, error) {
+	if f.resp == nil {
+		return nil, f.err
+	}
+	resp := *f.resp
+
+	return &resp, f.err
+}
@@ -0,0 +1,182 @@
+package amazonmws
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSingleflightCollapsesIdenticalRequests(t *testing.T) {
+	flights := &Singleflight{}
+	release := make(chan struct{})
+	var sent int64
+	amazon := func(next RoundTripper) RoundTripper {
+		return RoundTripperFunc(func(req *Request) (*Response, error) {
+			atomic.AddInt64(&sent, 1)
+			<-release
+			return &Response{StatusCode: 200, Body: "<" + req.Params["ASINList.ASIN.1"] + "/>"}, nil
+		})
+	}
+	api := AmazonMWSAPI{Host: "mws.amazonservices.com", Middleware: []Middleware{flights.Middleware, amazon}}
+
+	var wg sync.WaitGroup
+	bodies := make([]string, 5)
+	for i := range bodies {
+		asin := "B1"
+		if i == 4 {
+			asin = "B2"
+		}
+		wg.Add(1)
+		go func(i int, asin string) {
+			defer wg.Done()
+			bodies[i], _, _ = api.GetCompetitivePricingForASIN([]string{asin})
+		}(i, asin)
+	}
+
+	for flights.Shared() < 3 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int64(2), atomic.LoadInt64(&sent))
+	assert.Equal(t, []string{"<B1/>", "<B1/>", "<B1/>", "<B1/>", "<B2/>"}, bodies)
+}
+
+func TestSingleflightWaiterHonorsContext(t *testing.T) {
+	flights := &Singleflight{}
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{})
+	rt := flights.Middleware(RoundTripperFunc(func(req *Request) (*Response, error) {
+		close(started)
+		<-release
+		return &Response{}, nil
+	}))
+
+	params := map[string]string{"ASIN": "B1", "Timestamp": "2021-02-01T10:00:00Z"}
+	go rt.RoundTrip(&Request{Context: context.Background(), Action: "GetProductCategoriesForASIN", Params: params})
+	<-started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	later := map[string]string{"ASIN": "B1", "Timestamp": "2021-02-01T10:00:01Z"}
+	_, err := rt.RoundTrip(&Request{Context: ctx, Action: "GetProductCategoriesForASIN", Params: later})
+
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, int64(1), flights.Shared())
+}
+
+func TestSingleflightSurvivesPanic(t *testing.T) {
+	flights := &Singleflight{}
+	release := make(chan struct{})
+	var calls int64
+	rt := flights.Middleware(RoundTripperFunc(func(req *Request) (*Response, error) {
+		if atomic.AddInt64(&calls, 1) == 1 {
+			<-release
+			panic("boom")
+		}
+		return &Response{StatusCode: 200}, nil
+	}))
+
+	request := func() *Request {
+		return &Request{Context: context.Background(), Action: "GetProductCategoriesForASIN", Section: "/Products/2011-10-01", Params: map[string]string{"ASIN": "B1"}}
+	}
+
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := rt.RoundTrip(request())
+			errs <- err
+		}()
+	}
+	for flights.Shared() < 1 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+
+	for i := 0; i < 2; i++ {
+		assert.EqualError(t, <-errs, "amazonmws: GetProductCategoriesForASIN /Products/2011-10-01 panicked: boom")
+	}
+	resp, err := rt.RoundTrip(request())
+	assert.Nil(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+}
+
+func TestSingleflightWaitersSpendNoQuota(t *testing.T) {
+	flights := &Singleflight{}
+	release := make(chan struct{})
+	gate := func(next RoundTripper) RoundTripper {
+		return RoundTripperFunc(func(req *Request) (*Response, error) {
+			<-release
+			return next.RoundTrip(req)
+		})
+	}
+	api := AmazonMWSAPI{Host: offlineHost, Middleware: []Middleware{flights.Middleware, gate}}
+	limiter := NewLimiter(10, time.Hour)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fetchWithRetry(context.Background(), api.telemetry(), "GetCompetitivePricingForASIN", pacer{limiter: limiter}, 1, func(ctx context.Context) (string, Quota, error) {
+				return api.WithContext(ctx).GetCompetitivePricingForASIN([]string{"B1"})
+			})
+		}()
+	}
+	for flights.Shared() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	assert.True(t, limiter.available(9) <= 0)
+	assert.True(t, limiter.available(10) > 0)
+}
+
+func TestSingleflightOutlivesFirstCaller(t *testing.T) {
+	flights := &Singleflight{}
+	release := make(chan struct{})
+	started := make(chan struct{})
+	rt := flights.Middleware(RoundTripperFunc(func(req *Request) (*Response, error) {
+		close(started)
+		<-release
+		if err := req.Context.Err(); err != nil {
+			return nil, err
+		}
+		return &Response{StatusCode: 200, Body: "<B1/>"}, nil
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	params := map[string]string{"ASIN": "B1", "Timestamp": "2021-02-01T10:00:00Z"}
+	first := make(chan error, 1)
+	go func() {
+		_, err := rt.RoundTrip(&Request{Context: ctx, Action: "GetProductCategoriesForASIN", Params: params})
+		first <- err
+	}()
+	<-started
+
+	later := map[string]string{"ASIN": "B1", "Timestamp": "2021-02-01T10:00:01Z"}
+	second := make(chan *Response, 1)
+	go func() {
+		resp, err := rt.RoundTrip(&Request{Context: context.Background(), Action: "GetProductCategoriesForASIN", Params: later})
+		assert.NoError(t, err)
+		second <- resp
+	}()
+	for flights.Shared() < 1 {
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	assert.Equal(t, context.Canceled, <-first)
+	close(release)
+
+	resp := <-second
+	if assert.NotNil(t, resp) {
+		assert.Equal(t, "<B1/>", resp.Body)
+	}
+}
@@ -0,0 +1,184 @@
+package amazonmws
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"github.com/valyala/fasthttp"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// SQSQueue is a Queue backed by the Amazon SQS query protocol. It works with
+// SQS itself and with local emulators such as ElasticMQ or LocalStack.
+// Requests are signed with AWS Signature Version 4 when AccessKey is set and
+// sent unsigned otherwise, which emulators accept.
+type SQSQueue struct {
+	// QueueURL is the full queue URL, for example
+	// http://localhost:9324/000000000000/mws-notifications.
+	QueueURL string
+
+	Region    string
+	AccessKey string
+	SecretKey string
+
+	// WaitTimeSeconds enables long polling when greater than zero.
+	WaitTimeSeconds int
+
+	// VisibilityTimeout overrides the queue's visibility timeout when set.
+	VisibilityTimeout time.Duration
+}
+
+type sqsReceiveMessageResponse struct {
+	Messages []struct {
+		MessageId     string `xml:"MessageId"`
+		ReceiptHandle string `xml:"ReceiptHandle"`
+		Body          string `xml:"Body"`
+	} `xml:"ReceiveMessageResult>Message"`
+}
+
+func (q *SQSQueue) Receive(ctx context.Context, max int) ([]QueueMessage, error) {
+	if max > 10 {
+		max = 10
+	}
+
+	params := url.Values{}
+	params.Set("Action", "ReceiveMessage")
+	params.Set("MaxNumberOfMessages", strconv.Itoa(max))
+	if q.WaitTimeSeconds > 0 {
+		params.Set("WaitTimeSeconds", strconv.Itoa(q.WaitTimeSeconds))
+	}
+	if q.VisibilityTimeout > 0 {
+		params.Set("VisibilityTimeout", strconv.Itoa(int(q.VisibilityTimeout/time.Second)))
+	}
+
+	body, err := q.call(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp sqsReceiveMessageResponse
+	if err := xml.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+
+	messages := make([]QueueMessage, len(resp.Messages))
+	for i, m := range resp.Messages {
+		messages[i] = QueueMessage{MessageId: m.MessageId, ReceiptHandle: m.ReceiptHandle, Body: []byte(m.Body)}
+	}
+
+	return messages, nil
+}
+
+func (q *SQSQueue) Delete(ctx context.Context, msg QueueMessage) error {
+	params := url.Values{}
+	params.Set("Action", "DeleteMessage")
+	params.Set("ReceiptHandle", msg.ReceiptHandle)
+
+	_, err := q.call(ctx, params)
+	return err
+}
+
+// Send enqueues body. MWS publishes to the queue itself; Send exists so that
+// tests and emulators can inject notifications.
+func (q *SQSQueue) Send(ctx context.Context, body []byte) error {
+	params := url.Values{}
+	params.Set("Action", "SendMessage")
+	params.Set("MessageBody", string(body))
+
+	_, err := q.call(ctx, params)
+	return err
+}
+
+func (q *SQSQueue) call(ctx context.Context, params url.Values) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	queueURL, err := url.Parse(q.QueueURL)
+	if err != nil {
+		return nil, err
+	}
+
+	params.Set("Version", "2012-11-05")
+	payload := params.Encode()
+
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+
+	req.Header.SetMethodBytes(strPost)
+	req.SetRequestURI(q.QueueURL)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if q.AccessKey != "" {
+		now := time.Now().UTC()
+		req.Header.Set("X-Amz-Date", now.Format("20060102T150405Z"))
+		req.Header.Set("Authorization", q.authorization(queueURL, payload, now))
+	}
+	req.SetBodyString(payload)
+
+	timeout := time.Duration(q.WaitTimeSeconds)*time.Second + 10*time.Second
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < timeout {
+		timeout = time.Until(deadline)
+	}
+	if err := fasthttp.DoTimeout(req, resp, timeout); err != nil {
+		return nil, err
+	}
+
+	body := append([]byte(nil), resp.Body()...)
+	if mwsErr := parseErrorResponse(string(body)); mwsErr != nil {
+		return nil, mwsErr
+	}
+	if resp.StatusCode() >= 300 {
+		return nil, fmt.Errorf("amazonmws: sqs %s returned HTTP %d", params.Get("Action"), resp.StatusCode())
+	}
+
+	return body, nil
+}
+
+// authorization returns the Signature Version 4 Authorization header for a
+// form-encoded POST of payload to u.
+func (q *SQSQueue) authorization(u *url.URL, payload string, now time.Time) string {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	region := q.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	scope := date + "/" + region + "/sqs/aws4_request"
+	signedHeaders := "content-type;host;x-amz-date"
+
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	payloadHash := sha256.Sum256([]byte(payload))
+	canonicalRequest := "POST\n" + path + "\n\n" +
+		"content-type:application/x-www-form-urlencoded\n" +
+		"host:" + u.Host + "\n" +
+		"x-amz-date:" + amzDate + "\n\n" +
+		signedHeaders + "\n" +
+		hex.EncodeToString(payloadHash[:])
+
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+q.SecretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "sqs")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	return "AWS4-HMAC-SHA256 Credential=" + q.AccessKey + "/" + scope + ", SignedHeaders=" + signedHeaders + ", Signature=" + signature
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,104 @@
+package amazonmws
+
+import (
+	"strconv"
+)
+
+// DeliveryChannelSQS is the only delivery channel MWS supports.
+const DeliveryChannelSQS = "SQS"
+
+type AttributeKeyValue struct {
+	Key   string `xml:"Key"`
+	Value string `xml:"Value"`
+}
+
+// Destination is where Amazon delivers notifications.
+type Destination struct {
+	DeliveryChannel string              `xml:"DeliveryChannel"`
+	AttributeList   []AttributeKeyValue `xml:"AttributeList>member"`
+}
+
+// SQSDestination returns the destination for an Amazon SQS queue URL.
+func SQSDestination(queueURL string) Destination {
+	return Destination{
+		DeliveryChannel: DeliveryChannelSQS,
+		AttributeList:   []AttributeKeyValue{{Key: "sqsQueueUrl", Value: queueURL}},
+	}
+}
+
+func (d Destination) appendQuery(params map[string]string, prefix string) {
+	params[prefix+".DeliveryChannel"] = d.DeliveryChannel
+	for i, attr := range d.AttributeList {
+		key := prefix + ".AttributeList.member." + strconv.Itoa(i+1)
+		params[key+".Key"] = attr.Key
+		params[key+".Value"] = attr.Value
+	}
+}
+
+type Subscription struct {
+	NotificationType string      `xml:"NotificationType"`
+	Destination      Destination `xml:"Destination"`
+	IsEnabled        bool        `xml:"IsEnabled"`
+}
+
+// RegisterDestination registers a destination for the client's marketplace.
+func (api AmazonMWSAPI) RegisterDestination(destination Destination) (Quota, error) {
+	params := make(map[string]string)
+
+	params["MarketplaceId"] = api.MarketplaceId
+	destination.appendQuery(params, "Destination")
+
+	return api.fetchAndDecode("RegisterDestination", "/Subscriptions/2013-07-01", params, nil, nil)
+}
+
+// SendTestNotificationToDestination asks Amazon to send a Test notification to
+// a registered destination.
+func (api AmazonMWSAPI) SendTestNotificationToDestination(destination Destination) (Quota, error) {
+	params := make(map[string]string)
+
+	params["MarketplaceId"] = api.MarketplaceId
+	destination.appendQuery(params, "Destination")
+
+	return api.fetchAndDecode("SendTestNotificationToDestination", "/Subscriptions/2013-07-01", params, nil, nil)
+}
+
+// CreateSubscription subscribes a registered destination to a notification
+// type, such as NotificationTypeAnyOfferChanged.
+func (api AmazonMWSAPI) CreateSubscription(subscription Subscription) (Quota, error) {
+	params := make(map[string]string)
+
+	params["MarketplaceId"] = api.MarketplaceId
+	params["Subscription.NotificationType"] = subscription.NotificationType
+	subscription.Destination.appendQuery(params, "Subscription.Destination")
+	params["Subscription.IsEnabled"] = strconv.FormatBool(subscription.IsEnabled)
+
+	return api.fetchAndDecode("CreateSubscription", "/Subscriptions/2013-07-01", params, nil, nil)
+}
+
+type listSubscriptionsResponse struct {
+	SubscriptionList []Subscription   `xml:"ListSubscriptionsResult>SubscriptionList>member"`
+	ResponseMetadata ResponseMetadata `xml:"ResponseMetadata"`
+}
+
+// ListSubscriptions returns the subscriptions of the client's marketplace.
+func (api AmazonMWSAPI) ListSubscriptions() ([]Subscription, Quota, error) {
+	params := make(map[string]string)
+	params["MarketplaceId"] = api.MarketplaceId
+
+	var resp listSubscriptionsResponse
+	quota, err := api.fetchAndDecode("ListSubscriptions", "/Subscriptions/2013-07-01", params, nil, &resp)
+
+	return resp.SubscriptionList, quota, err
+}
+
+// DeleteSubscription removes the subscription of destination to
+// notificationType.
+func (api AmazonMWSAPI) DeleteSubscription(notificationType string, destination Destination) (Quota, error) {
+	params := make(map[string]string)
+
+	params["MarketplaceId"] = api.MarketplaceId
+	params["NotificationType"] = notificationType
+	destination.appendQuery(params, "Destination")
+
+	return api.fetchAndDecode("DeleteSubscription", "/Subscriptions/2013-07-01", params, nil, nil)
+}
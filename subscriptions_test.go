@@ -0,0 +1,107 @@
+package amazonmws
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const queueURL = "https://sqs.us-east-1.amazonaws.com/51471EXAMPLE/mws_notifications"
+
+func TestDestinationQuery(t *testing.T) {
+	destination := SQSDestination(queueURL)
+	destination.AttributeList = append(destination.AttributeList, AttributeKeyValue{Key: "region", Value: "us-east-1"})
+
+	params := make(map[string]string)
+	destination.appendQuery(params, "Destination")
+
+	assert.Equal(t, map[string]string{
+		"Destination.DeliveryChannel":              "SQS",
+		"Destination.AttributeList.member.1.Key":   "sqsQueueUrl",
+		"Destination.AttributeList.member.1.Value": queueURL,
+		"Destination.AttributeList.member.2.Key":   "region",
+		"Destination.AttributeList.member.2.Value": "us-east-1",
+	}, params)
+}
+
+func TestCreateSubscriptionQuery(t *testing.T) {
+	var seen []*Request
+	api := AmazonMWSAPI{
+		Host:          "mws.amazonservices.com",
+		MarketplaceId: "ATVPDKIKX0DER",
+		Middleware:    []Middleware{amazonReturning(`<CreateSubscriptionResponse/>`, &seen)},
+	}
+
+	_, err := api.CreateSubscription(Subscription{
+		NotificationType: NotificationTypeAnyOfferChanged,
+		Destination:      SQSDestination(queueURL),
+		IsEnabled:        true,
+	})
+
+	assert.Nil(t, err)
+	assert.Len(t, seen, 1)
+	assert.Equal(t, "CreateSubscription", seen[0].Action)
+	assert.Equal(t, "/Subscriptions/2013-07-01", seen[0].Section)
+
+	params := seen[0].Params
+	assert.Equal(t, "ATVPDKIKX0DER", params["MarketplaceId"])
+	assert.Equal(t, "AnyOfferChanged", params["Subscription.NotificationType"])
+	assert.Equal(t, "SQS", params["Subscription.Destination.DeliveryChannel"])
+	assert.Equal(t, "sqsQueueUrl", params["Subscription.Destination.AttributeList.member.1.Key"])
+	assert.Equal(t, queueURL, params["Subscription.Destination.AttributeList.member.1.Value"])
+	assert.Equal(t, "true", params["Subscription.IsEnabled"])
+}
+
+func TestDeleteSubscriptionQuery(t *testing.T) {
+	var seen []*Request
+	api := AmazonMWSAPI{
+		Host:          "mws.amazonservices.com",
+		MarketplaceId: "ATVPDKIKX0DER",
+		Middleware:    []Middleware{amazonReturning(`<DeleteSubscriptionResponse/>`, &seen)},
+	}
+
+	_, err := api.DeleteSubscription(NotificationTypeAnyOfferChanged, SQSDestination(queueURL))
+
+	assert.Nil(t, err)
+	params := seen[0].Params
+	assert.Equal(t, "AnyOfferChanged", params["NotificationType"])
+	assert.Equal(t, "SQS", params["Destination.DeliveryChannel"])
+	assert.Equal(t, queueURL, params["Destination.AttributeList.member.1.Value"])
+	assert.NotContains(t, params, "Subscription.NotificationType")
+}
+
+func TestListSubscriptions(t *testing.T) {
+	body := `<ListSubscriptionsResponse xmlns="https://mws.amazonservices.com/Subscriptions/2013-07-01">
+  <ListSubscriptionsResult>
+    <SubscriptionList>
+      <member>
+        <NotificationType>AnyOfferChanged</NotificationType>
+        <Destination>
+          <DeliveryChannel>SQS</DeliveryChannel>
+          <AttributeList>
+            <member><Key>sqsQueueUrl</Key><Value>https://sqs.us-east-1.amazonaws.com/51471EXAMPLE/mws_notifications</Value></member>
+          </AttributeList>
+        </Destination>
+        <IsEnabled>true</IsEnabled>
+      </member>
+    </SubscriptionList>
+  </ListSubscriptionsResult>
+  <ResponseMetadata><RequestId>7ee6d8a4-40e9-4a5d-9b6f-b3e4ee3b4bf5</RequestId></ResponseMetadata>
+</ListSubscriptionsResponse>`
+
+	var seen []*Request
+	api := AmazonMWSAPI{
+		Host:          "mws.amazonservices.com",
+		MarketplaceId: "ATVPDKIKX0DER",
+		Middleware:    []Middleware{amazonReturning(body, &seen)},
+	}
+
+	subscriptions, _, err := api.ListSubscriptions()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "ATVPDKIKX0DER", seen[0].Params["MarketplaceId"])
+	assert.Equal(t, []Subscription{{
+		NotificationType: NotificationTypeAnyOfferChanged,
+		Destination:      SQSDestination(queueURL),
+		IsEnabled:        true,
+	}}, subscriptions)
+}
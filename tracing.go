@@ -0,0 +1,133 @@
+package amazonmws
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// Tracer starts spans. Every request produces a span named "MWS <Action>"
+// with the children "mws.sign", "mws.http" and, for typed methods,
+// "mws.decode". The request span carries these attributes:
+//   - mws.action and mws.section
+//   - mws.seller_id_hash: a SHA-256 prefix of SellerId, never the id itself
+//   - mws.marketplace_id
+//   - mws.request_id: Amazon's RequestId, once a response arrived
+//   - http.status_code
+//   - mws.retry_attempt: 0 for the first try, counting throttle retries
+//
+// The otelmws package adapts an OpenTelemetry tracer.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a single traced operation. Attribute values are strings, ints,
+// float64s or bools.
+type Span interface {
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+type nopTracer struct{}
+
+func (nopTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	return ctx, nopSpan{}
+}
+
+type nopSpan struct{}
+
+func (nopSpan) SetAttribute(key string, value interface{}) {}
+func (nopSpan) RecordError(err error)                      {}
+func (nopSpan) End()                                       {}
+
+type contextKey int
+
+const (
+	requestSpanKey contextKey = iota
+	retryAttemptKey
+)
+
+// WithContext returns a copy of api whose requests are traced as children of
+// the span in ctx. Methods that take a context use it in the same way.
+func (api AmazonMWSAPI) WithContext(ctx context.Context) AmazonMWSAPI {
+	api.ctx = ctx
+	return api
+}
+
+func (api AmazonMWSAPI) context() context.Context {
+	if api.ctx == nil {
+		return context.Background()
+	}
+
+	return api.ctx
+}
+
+// startRequestSpan starts the span covering one call to action and returns a
+// copy of api that sends its requests within it.
+func (api AmazonMWSAPI) startRequestSpan(tracer Tracer, action, section string, params map[string]string) (AmazonMWSAPI, Span) {
+	ctx, span := tracer.Start(api.context(), "MWS "+action)
+	span.SetAttribute("mws.action", action)
+	span.SetAttribute("mws.section", section)
+	if api.SellerId != "" {
+		span.SetAttribute("mws.seller_id_hash", hashSellerId(api.SellerId))
+	}
+	marketplaceId := params["MarketplaceId"]
+	if marketplaceId == "" {
+		marketplaceId = api.MarketplaceId
+	}
+	if marketplaceId != "" {
+		span.SetAttribute("mws.marketplace_id", marketplaceId)
+	}
+
+	return api.WithContext(context.WithValue(ctx, requestSpanKey, span)), span
+}
+
+// requestSpan returns the span started by startRequestSpan for ctx.
+func requestSpan(ctx context.Context) Span {
+	if span, ok := ctx.Value(requestSpanKey).(Span); ok {
+		return span
+	}
+
+	return nopSpan{}
+}
+
+func withRetryAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, retryAttemptKey, attempt)
+}
+
+func retryAttempt(ctx context.Context) int {
+	attempt, _ := ctx.Value(retryAttemptKey).(int)
+	return attempt
+}
+
+func endSpan(span Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+func hashSellerId(sellerId string) string {
+	sum := sha256.Sum256([]byte(sellerId))
+	return hex.EncodeToString(sum[:8])
+}
+
+// scanRequestId finds the RequestId of a response without decoding it.
+func scanRequestId(body string) string {
+	for _, tag := range []string{"RequestId", "RequestID"} {
+		start := strings.Index(body, "<"+tag+">")
+		if start < 0 {
+			continue
+		}
+		start += len(tag) + 2
+		end := strings.Index(body[start:], "</"+tag+">")
+		if end < 0 {
+			continue
+		}
+		return strings.TrimSpace(body[start : start+end])
+	}
+
+	return ""
+}
@@ -0,0 +1,162 @@
+package amazonmws
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordedSpan struct {
+	name   string
+	parent *recordedSpan
+	attrs  map[string]interface{}
+	errs   []error
+	ended  bool
+	tracer *recordingTracer
+}
+
+func (s *recordedSpan) SetAttribute(key string, value interface{}) {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.attrs[key] = value
+}
+
+func (s *recordedSpan) RecordError(err error) {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.errs = append(s.errs, err)
+}
+
+func (s *recordedSpan) End() {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.ended = true
+}
+
+type spanKey struct{}
+
+type failingProvider struct {
+	err error
+}
+
+func (p failingProvider) Retrieve() (Credentials, error) {
+	return Credentials{}, p.err
+}
+
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	parent, _ := ctx.Value(spanKey{}).(*recordedSpan)
+	span := &recordedSpan{name: name, parent: parent, attrs: make(map[string]interface{}), tracer: t}
+	t.spans = append(t.spans, span)
+
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func TestStartRequestSpanAttributes(t *testing.T) {
+	tracer := &recordingTracer{}
+	api := AmazonMWSAPI{SellerId: "A1SELLER", MarketplaceId: "ATVPDKIKX0DER"}
+
+	traced, span := api.startRequestSpan(tracer, "ListOrders", "/Orders/2013-09-01", map[string]string{})
+	span.End()
+
+	assert.Len(t, tracer.spans, 1)
+	root := tracer.spans[0]
+	assert.Equal(t, "MWS ListOrders", root.name)
+	assert.Equal(t, "ListOrders", root.attrs["mws.action"])
+	assert.Equal(t, "/Orders/2013-09-01", root.attrs["mws.section"])
+	assert.Equal(t, hashSellerId("A1SELLER"), root.attrs["mws.seller_id_hash"])
+	assert.NotContains(t, root.attrs["mws.seller_id_hash"], "A1SELLER")
+	assert.Equal(t, "ATVPDKIKX0DER", root.attrs["mws.marketplace_id"])
+	assert.True(t, root.ended)
+	assert.Equal(t, span, requestSpan(traced.context()))
+}
+
+func TestRequestSpanRecordsFailure(t *testing.T) {
+	tracer := &recordingTracer{}
+	failing := errors.New("vault unavailable")
+	api := AmazonMWSAPI{
+		Host:        "mws.amazonservices.com",
+		SellerId:    "A1SELLER",
+		Credentials: failingProvider{err: failing},
+		Tracer:      tracer,
+	}
+
+	var resp getServiceStatusResponse
+	_, err := api.fetchAndDecode("GetServiceStatus", "/Sellers/2011-07-01", map[string]string{}, nil, &resp)
+
+	assert.Equal(t, failing, err)
+	assert.Len(t, tracer.spans, 1)
+	assert.Equal(t, "MWS GetServiceStatus", tracer.spans[0].name)
+	assert.Equal(t, []error{failing}, tracer.spans[0].errs)
+	assert.True(t, tracer.spans[0].ended)
+}
+
+func TestRequestSpanRecordsErrorResponse(t *testing.T) {
+	tracer := &recordingTracer{}
+	body := `<ErrorResponse><Error><Type>Sender</Type><Code>InvalidParameterValue</Code><Message>Invalid ASIN</Message></Error><RequestID>r-1</RequestID></ErrorResponse>`
+	var seen []*Request
+	api := AmazonMWSAPI{
+		Host:       "mws.amazonservices.com",
+		SellerId:   "A1SELLER",
+		Tracer:     tracer,
+		Middleware: []Middleware{amazonReturning(body, &seen)},
+	}
+
+	var resp getServiceStatusResponse
+	_, err := api.fetchAndDecode("GetServiceStatus", "/Sellers/2011-07-01", map[string]string{}, nil, &resp)
+
+	mwsErr := &MWSError{Type: "Sender", Code: "InvalidParameterValue", Message: "Invalid ASIN", RequestId: "r-1"}
+	assert.Equal(t, mwsErr, err)
+	assert.Len(t, tracer.spans, 2)
+	root, decode := tracer.spans[0], tracer.spans[1]
+	assert.Equal(t, "mws.decode", decode.name)
+	assert.Equal(t, root, decode.parent)
+	assert.Equal(t, []error{mwsErr}, decode.errs)
+	assert.Equal(t, []error{mwsErr}, root.errs)
+	assert.True(t, root.ended)
+}
+
+func TestCallSpanCoversRetries(t *testing.T) {
+	tracer := &recordingTracer{}
+	limiter := NewLimiter(10, time.Millisecond)
+
+	traced, span := AmazonMWSAPI{}.startRequestSpan(tracer, "ListOrders", "/Orders/2013-09-01", map[string]string{})
+	var attempts []int
+	_, _, err := fetchWithRetry(traced.context(), AmazonMWSAPI{}.telemetry(), "ListOrders", pacer{limiter: limiter}, 1, func(ctx context.Context) (string, Quota, error) {
+		attempts = append(attempts, retryAttempt(ctx))
+		assert.Equal(t, span, requestSpan(ctx))
+		if len(attempts) == 1 {
+			return `<ErrorResponse><Error><Type>Sender</Type><Code>RequestThrottled</Code><Message>Request is throttled</Message></Error><RequestID>r-1</RequestID></ErrorResponse>`, Quota{}, nil
+		}
+		return "<ListOrdersResponse/>", Quota{}, nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []int{0, 1}, attempts)
+}
+
+func TestTracingIsANoOpByDefault(t *testing.T) {
+	tel := AmazonMWSAPI{}.telemetry()
+	ctx, span := tel.tracer.Start(context.Background(), "MWS ListOrders")
+
+	assert.Equal(t, context.Background(), ctx)
+	assert.Equal(t, nopSpan{}, span)
+	assert.Equal(t, nopSpan{}, requestSpan(context.Background()))
+	assert.Equal(t, context.Background(), AmazonMWSAPI{}.context())
+}
+
+func TestScanRequestId(t *testing.T) {
+	assert.Equal(t, "r-1", scanRequestId(`<ListOrdersResponse><ResponseMetadata><RequestId> r-1 </RequestId></ResponseMetadata></ListOrdersResponse>`))
+	assert.Equal(t, "r-2", scanRequestId(`<ErrorResponse><RequestID>r-2</RequestID></ErrorResponse>`))
+	assert.Equal(t, "", scanRequestId(`<ListOrdersResponse/>`))
+}
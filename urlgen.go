@@ -1,29 +1,57 @@
 package amazonmws
 
 import (
-	"bytes"
-	"crypto/hmac"
+	"context"
 	"crypto/md5"
-	"crypto/sha256"
 	"encoding/base64"
-	"fmt"
 	"github.com/valyala/fasthttp"
+	"io"
+	"net/http"
 	"net/url"
-	"sort"
 	"strconv"
-	"strings"
+	"sync"
 	"time"
 	//"bitbucket.org/zombiezen/cardcpx/natsort"
 )
 
-var versions map[string]string
+var (
+	versionsMu sync.RWMutex
+	versions   map[string]string
+)
 
 func init() {
 	versions = make(map[string]string)
+	versions["/EasyShip/2018-09-01"] = "2018-09-01"
 	versions["/Feeds/2009-01-01"] = "2009-01-01"
+	versions["/Finances/2015-05-01"] = "2015-05-01"
+	versions["/FulfillmentInboundShipment/2010-10-01"] = "2010-10-01"
+	versions["/FulfillmentInventory/2010-10-01"] = "2010-10-01"
+	versions["/FulfillmentOutboundShipment/2010-10-01"] = "2010-10-01"
+	versions["/MerchantFulfillment/2015-06-01"] = "2015-06-01"
 	versions["/Products/2011-10-01"] = "2011-10-01"
+	versions["/Recommendations/2013-04-01"] = "2013-04-01"
 	versions["/Reports/2009-01-01"] = "2009-01-01"
 	versions["/Sellers/2011-07-01"] = "2011-07-01"
+	versions["/ShipmentInvoicing/2018-09-01"] = "2018-09-01"
+	versions["/Subscriptions/2013-07-01"] = "2013-07-01"
+}
+
+// RegisterSection makes the API section at path, such as
+// "/Orders/2013-09-01", known to the client with the given version. It may
+// be called at any time, including while requests are in flight.
+func RegisterSection(section, version string) {
+	versionsMu.Lock()
+	defer versionsMu.Unlock()
+
+	versions[section] = version
+}
+
+func sectionVersion(section string) (string, bool) {
+	versionsMu.RLock()
+	defer versionsMu.RUnlock()
+
+	version, ok := versions[section]
+	return version, ok
 }
 
 type AmazonMWSAPI struct {
@@ -33,6 +61,37 @@ type AmazonMWSAPI struct {
 	AuthToken     string
 	MarketplaceId string
 	SellerId      string
+
+	// Credentials, when set, is asked for the keys on every request and
+	// takes precedence over AccessKey, SecretKey and AuthToken.
+	Credentials CredentialsProvider
+
+	// Dump, when set, receives every request and response with the access
+	// key, auth token and signature masked.
+	Dump io.Writer
+
+	// Logger, when set, receives structured events for every request.
+	Logger Logger
+
+	// Metrics, when set, receives request counts, latencies, throttles and
+	// the remaining quota; see PrometheusMetrics.
+	Metrics Metrics
+
+	// Tracer, when set, receives a span for every request; see Tracer.
+	Tracer Tracer
+
+	// Middleware wraps every request, in order; see Middleware.
+	Middleware []Middleware
+
+	// Cache, when set, answers repeated catalog lookups without calling
+	// Amazon; see ResponseCache.
+	Cache *ResponseCache
+
+	// Scheduler, when set, orders requests waiting for quota by priority;
+	// see Scheduler.
+	Scheduler *Scheduler
+
+	ctx context.Context
 }
 
 type Quota struct {
@@ -52,65 +111,160 @@ func (q *Quota) RetryIn() time.Duration {
 var strPost = []byte("POST")
 
 func (api AmazonMWSAPI) fastSignAndFetchViaPost(Action string, ActionPath string, Parameters map[string]string, body []byte) (string, Quota, error) {
-	genUrl, err := GenerateAmazonUrlPost(api, ActionPath)
+	version, exists := sectionVersion(ActionPath)
+	if !exists {
+		return "", Quota{}, unknownSectionError(ActionPath)
+	}
+
+	api, span := api.startRequestSpan(api.telemetry().tracer, Action, ActionPath, Parameters)
+	responseBody, quota, err := api.signAndFetch(Action, ActionPath, version, Parameters, body)
+	endSpan(span, err)
+
+	return responseBody, quota, err
+}
+
+// fetchAndDecode sends a request like fastSignAndFetchViaPost and decodes the
+// response into v, or only checks it for an error when v is nil.
+func (api AmazonMWSAPI) fetchAndDecode(Action string, ActionPath string, Parameters map[string]string, body []byte, v interface{}) (Quota, error) {
+	version, exists := sectionVersion(ActionPath)
+	if !exists {
+		return Quota{}, unknownSectionError(ActionPath)
+	}
+
+	tracer := api.telemetry().tracer
+	api, span := api.startRequestSpan(tracer, Action, ActionPath, Parameters)
+	responseBody, quota, err := api.signAndFetch(Action, ActionPath, version, Parameters, body)
 	if err != nil {
-		return "", Quota{}, err
+		endSpan(span, err)
+		return quota, err
 	}
 
-	req := fasthttp.AcquireRequest()
-	resp := fasthttp.AcquireResponse()
-	defer fasthttp.ReleaseRequest(req)   // <- do not forget to release
-	defer fasthttp.ReleaseResponse(resp) // <- do not forget to release
+	_, decodeSpan := tracer.Start(api.context(), "mws.decode")
+	if v == nil {
+		err = checkResponse(responseBody)
+	} else {
+		err = decodeResponse(responseBody, v)
+	}
+	endSpan(decodeSpan, err)
+	endSpan(span, err)
 
-	if api.AuthToken != "" {
-		Parameters["MWSAuthToken"] = api.AuthToken
+	return quota, err
+}
+
+func (api AmazonMWSAPI) signAndFetch(Action string, ActionPath string, version string, Parameters map[string]string, body []byte) (string, Quota, error) {
+	creds, err := api.credentials()
+	if err != nil {
+		return "", Quota{}, err
 	}
 
-	var exists bool
-	Parameters["Action"] = Action
-	Parameters["AWSAccessKeyId"] = api.AccessKey
+	requestSpan(api.context()).SetAttribute("mws.retry_attempt", retryAttempt(api.context()))
+
+	setAuthParams(Parameters, creds, Action, version)
 	Parameters["SellerId"] = api.SellerId
-	Parameters["SignatureVersion"] = "2"
-	Parameters["SignatureMethod"] = "HmacSHA256"
-	Parameters["Version"], exists = versions[ActionPath]
-	if !exists {
-		fmt.Printf("Could not load version for %s\n", ActionPath)
-	}
 	Parameters["Timestamp"] = time.Now().UTC().Format(time.RFC3339)
 
-	signature, err := sign("POST", genUrl, Parameters, api)
+	resp, err := api.roundTripper().RoundTrip(&Request{
+		Context:   api.context(),
+		Action:    Action,
+		Section:   ActionPath,
+		Version:   version,
+		Params:    Parameters,
+		Body:      body,
+		Header:    make(http.Header),
+		secretKey: creds.SecretKey,
+	})
 	if err != nil {
 		return "", Quota{}, err
 	}
-	Parameters["Signature"] = signature
-	req.Header.SetMethodBytes(strPost)
-	req.SetRequestURI(genUrl.String())
 
-	v := url.Values{}
-	for index, value := range Parameters {
-		v.Set(index, value)
+	return resp.Body, resp.Quota, nil
+}
+
+// send waits for quota, signs r and sends it to Amazon. It is the innermost
+// RoundTripper of every client, so requests answered by middleware spend no
+// quota.
+func (api AmazonMWSAPI) send(r *Request) (*Response, error) {
+	api = api.WithContext(r.Context)
+	Action, ActionPath := r.Action, r.Section
+	// Middleware may have sent the request on behalf of another seller.
+	sellerId := r.Params["SellerId"]
+
+	pc, ok := pacingFrom(api.context())
+	if !ok {
+		pc = pacing{pacer: api.pacerFor(sellerId, Action, ActionPath), n: 1}
+	} else if pc.pacer.sellerId != sellerId || sectionQuotas[pc.pacer.action] {
+		// The pacer carried by ctx was chosen before middleware had its say
+		// and without the section.
+		pc.pacer = api.pacerFor(sellerId, pc.pacer.action, ActionPath)
+	}
+	done, err := pc.pacer.wait(api.context(), pc.n)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	// Quota may have kept the request waiting; stamp it as it leaves.
+	r.Params["Timestamp"] = time.Now().UTC().Format(time.RFC3339)
+
+	genUrl, err := GenerateAmazonUrlPost(api, ActionPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseRequest(req)   // <- do not forget to release
+	defer fasthttp.ReleaseResponse(resp) // <- do not forget to release
+
+	tel := api.telemetry()
+	logger := tel.logger
+	span := requestSpan(api.context())
+
+	_, signSpan := tel.tracer.Start(api.context(), "mws.sign")
+	s, err := Signer{SecretKey: r.secretKey}.SignQuery("POST", genUrl.Host, genUrl.Path, r.Params)
+	endSpan(signSpan, err)
+	if err != nil {
+		return nil, err
 	}
-	s := v.Encode()
+	req.Header.SetMethodBytes(strPost)
+	req.SetRequestURI(genUrl.String())
 
-	if body != nil {
+	if r.Body != nil {
 		req.SetRequestURI(string(req.RequestURI()) + "?" + s)
 
-		hash := md5.Sum(body)
+		hash := md5.Sum(r.Body)
 		MD5 := base64.StdEncoding.EncodeToString([]byte(hash[:]))
 
 		req.Header.DisableNormalizing()
 		req.Header.Set("Content-MD5", MD5)
 		req.Header.Set("Content-Type", "text/xml; charset=iso-8859-1")
-		req.SetBody(body)
+		req.SetBody(r.Body)
 	} else {
 		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 		req.Header.Set("ContentLength", strconv.Itoa(len([]byte(s))))
 		req.SetBodyString(s)
 	}
+	for key, values := range r.Header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	logger.Debug("mws request", "action", Action, "section", ActionPath, "seller_id", sellerId)
+	start := time.Now()
 
-	err = fasthttp.Do(req, resp)
+	_, httpSpan := tel.tracer.Start(api.context(), "mws.http")
+	err = doContext(api.context(), req, resp)
+	if err == nil {
+		httpSpan.SetAttribute("http.status_code", resp.StatusCode())
+	}
+	endSpan(httpSpan, err)
+	if api.Dump != nil {
+		dumpExchange(api.Dump, req, resp, err)
+	}
 	if err != nil {
-		return "", Quota{}, err
+		logger.Error("mws request failed", "action", Action, "section", ActionPath, "seller_id", sellerId, "duration", time.Since(start), "error", err)
+		tel.metrics.ObserveRequest(Action, ActionPath, "error", time.Since(start))
+		return nil, err
 	}
 
 	//resp.Header.Peek("x-mws-quota-max")
@@ -124,8 +278,70 @@ func (api AmazonMWSAPI) fastSignAndFetchViaPost(Action string, ActionPath string
 		MwsQuotaRemaining: remaining,
 		MwsQuotaResetsOn:  t,
 	}
+	pc.pacer.limiter.Update(quota)
 
-	return string(resp.Body()), quota, nil
+	responseBody := string(resp.Body())
+	span.SetAttribute("http.status_code", resp.StatusCode())
+	if requestId := scanRequestId(responseBody); requestId != "" {
+		span.SetAttribute("mws.request_id", requestId)
+	}
+	tel.metrics.ObserveRequest(Action, ActionPath, strconv.Itoa(resp.StatusCode()), time.Since(start))
+	if len(resp.Header.Peek("x-mws-quota-remaining")) > 0 {
+		tel.metrics.SetQuotaRemaining(Action, sellerId, remaining)
+	}
+	logger.Debug("mws response", "action", Action, "section", ActionPath, "seller_id", sellerId, "status", resp.StatusCode(), "duration", time.Since(start), "quota_remaining", remaining)
+	if mwsErr := parseErrorResponse(responseBody); mwsErr != nil {
+		span.RecordError(mwsErr)
+		if mwsErr.IsThrottled() {
+			pc.pacer.limiter.Backoff()
+			tel.metrics.IncThrottle(Action, sellerId)
+			logger.Warn("mws throttled", "action", Action, "section", ActionPath, "seller_id", sellerId, "request_id", mwsErr.RequestId, "quota_resets_on", t)
+		} else {
+			logger.Error("mws request failed", "action", Action, "section", ActionPath, "seller_id", sellerId, "request_id", mwsErr.RequestId, "error", mwsErr)
+		}
+	}
+
+	return &Response{StatusCode: resp.StatusCode(), Body: responseBody, Quota: quota}, nil
+}
+
+// doContext sends req unless ctx is already done, giving up at ctx's
+// deadline. fasthttp cannot abort an exchange when ctx is canceled, so the
+// response is discarded and ctx's error returned instead.
+func doContext(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var err error
+	if deadline, ok := ctx.Deadline(); ok {
+		err = fasthttp.DoDeadline(req, resp, deadline)
+		if err == fasthttp.ErrTimeout {
+			// fasthttp may give up a moment before the context's own
+			// timer fires; report the expiry the caller asked for.
+			<-ctx.Done()
+		}
+	} else {
+		err = fasthttp.Do(req, resp)
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+
+	return err
+}
+
+// setAuthParams sets the parameters every signed request carries except
+// SellerId and Timestamp or Expires.
+func setAuthParams(params map[string]string, creds Credentials, action, version string) {
+	if creds.AuthToken != "" {
+		params["MWSAuthToken"] = creds.AuthToken
+	}
+
+	params["Action"] = action
+	params["AWSAccessKeyId"] = creds.AccessKey
+	params["SignatureVersion"] = "2"
+	params["SignatureMethod"] = "HmacSHA256"
+	params["Version"] = version
 }
 
 func GenerateAmazonUrlPost(api AmazonMWSAPI, ActionPath string) (finalUrl *url.URL, err error) {
@@ -151,93 +367,22 @@ func SetTimestamp(origUrl *url.URL) (err error) {
 	return nil
 }
 
+// sign returns the Signature Version 2 signature of a request to origUrl.
 func sign(method string, origUrl *url.URL, params map[string]string, api AmazonMWSAPI) (string, error) {
-	paramMap := make(map[string]string)
-	for key, value := range params {
-		if key == "ReportOptions" {
-			paramMap[key] = url.QueryEscape(value)
-		} else {
-			paramMap[key] = value
-		}
-	}
-	paramMap["Timestamp"] = url.QueryEscape(paramMap["Timestamp"])
-
-	keys := make([]string, len(paramMap))
-	count := 0
-	for k, _ := range paramMap {
-		keys[count] = k
-		count++
-	}
-	sort.Strings(keys)
-
-	sortedParams := make([]string, len(paramMap))
-	count = 0
-	for _, k := range keys {
-		var buffer bytes.Buffer
-		buffer.WriteString(k)
-		buffer.WriteString("=")
-		buffer.WriteString(paramMap[k])
-		sortedParams[count] = buffer.String()
-		count++
-	}
-
-	stringParams := strings.Join(sortedParams, "&")
-
-	//toSign := fmt.Sprintf("%s\n%s\n%s\n%s", method, origUrl.Host, "/", stringParams)
-	toSign := fmt.Sprintf("%s\n%s\n%s\n%s", method, origUrl.Host, origUrl.Path, stringParams)
-
-	hasher := hmac.New(sha256.New, []byte(api.SecretKey))
-	_, err := hasher.Write([]byte(toSign))
+	creds, err := api.credentials()
 	if err != nil {
 		return "", err
 	}
 
-	hash := base64.StdEncoding.EncodeToString(hasher.Sum(nil))
-
-	return hash, nil
+	return Signer{SecretKey: creds.SecretKey}.Sign(method, origUrl.Host, origUrl.Path, params)
 }
 
+// SignAmazonUrl signs origUrl as a GET request; see Signer.SignURL.
 func SignAmazonUrl(origUrl *url.URL, api AmazonMWSAPI) (signedUrl string, err error) {
-	escapeUrl := strings.Replace(origUrl.RawQuery, ",", "%2C", -1)
-	escapeUrl = strings.Replace(escapeUrl, ":", "%3A", -1)
-
-	params := strings.Split(escapeUrl, "&")
-	paramMap := make(map[string]string)
-	keys := make([]string, len(params))
-
-	for k, v := range params {
-		parts := strings.Split(v, "=")
-		paramMap[parts[0]] = parts[1]
-		keys[k] = parts[0]
-	}
-	sort.Strings(keys)
-
-	sortedParams := make([]string, len(params))
-	for k, _ := range params {
-		var buffer bytes.Buffer
-		buffer.WriteString(keys[k])
-		buffer.WriteString("=")
-		buffer.WriteString(paramMap[keys[k]])
-		sortedParams[k] = buffer.String()
-	}
-
-	stringParams := strings.Join(sortedParams, "&")
-
-	toSign := fmt.Sprintf("GET\n%s\n%s\n%s", origUrl.Host, origUrl.Path, stringParams)
-
-	hasher := hmac.New(sha256.New, []byte(api.SecretKey))
-	_, err = hasher.Write([]byte(toSign))
+	creds, err := api.credentials()
 	if err != nil {
 		return "", err
 	}
 
-	hash := base64.StdEncoding.EncodeToString(hasher.Sum(nil))
-
-	hash = url.QueryEscape(hash)
-
-	newParams := fmt.Sprintf("%s&Signature=%s", stringParams, hash)
-
-	origUrl.RawQuery = newParams
-
-	return origUrl.String(), nil
+	return Signer{SecretKey: creds.SecretKey}.SignURL(origUrl)
 }